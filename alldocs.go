@@ -0,0 +1,255 @@
+package cloudant
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"go.uber.org/zap"
+)
+
+// defaultPageSize is the number of rows requested for every page, when not set in the options
+const defaultPageSize = 1000
+
+// AllDocsOptions is delegated to customize the rows returned by AllDocs
+// https://cloud.ibm.com/docs/services/Cloudant?topic=cloudant-databases#get-documents
+type AllDocsOptions struct {
+	// Return the content of the documents in the rows
+	IncludeDocs bool
+	// Return the conflicted revisions of the documents, only with IncludeDocs
+	Conflicts bool
+	// Return the rows in reverse order, StartKey have to be greater than EndKey
+	Descending bool
+	// Return only the documents with an `_id` greater or equal than the given one
+	StartKey string
+	// Return only the documents with an `_id` lower or equal than the given one
+	EndKey string
+	// Include the document with `_id` equal to EndKey, true if not set
+	InclusiveEnd *bool
+	// Return only the documents with the given `_id`, in the same order. Paging is not used
+	Keys []string
+	// Number of rows to skip
+	Skip int
+	// Whether the index is updated before returning the result (UpdateTrue, UpdateFalse, UpdateLazy)
+	Update string
+	// Use the same set of shards for every request, the result can be less updated
+	Stable bool
+	// Maximum number of rows returned, 0 for no limit
+	Limit int
+	// Number of rows requested for every page by AllDocs, 1000 if not set
+	PageSize int
+}
+
+// viewOptions is delegated to convert the options in the ones used for encode the request, excluding the limit
+func (opts AllDocsOptions) viewOptions() ViewOptions {
+	vo := ViewOptions{
+		IncludeDocs:  opts.IncludeDocs,
+		Conflicts:    opts.Conflicts,
+		Descending:   opts.Descending,
+		InclusiveEnd: opts.InclusiveEnd,
+		Skip:         opts.Skip,
+		Update:       opts.Update,
+		Stable:       opts.Stable,
+	}
+	if opts.StartKey != "" {
+		vo.StartKey = opts.StartKey
+	}
+	if opts.EndKey != "" {
+		vo.EndKey = opts.EndKey
+	}
+	if opts.Keys != nil {
+		vo.Keys = make([]interface{}, len(opts.Keys))
+		for i := range opts.Keys {
+			vo.Keys[i] = opts.Keys[i]
+		}
+	}
+	return vo
+}
+
+// AllDocsRow is delegated to save a row returned by `_all_docs`
+type AllDocsRow struct {
+	// Key of the document
+	ID string `json:"id"`
+	// Key of the row, the same of ID
+	Key string `json:"key"`
+	// Current revision of the document
+	Value struct {
+		Rev     string `json:"rev"`
+		Deleted bool   `json:"deleted,omitempty"`
+	} `json:"value"`
+	// Content of the document, only with IncludeDocs
+	Doc json.RawMessage `json:"doc,omitempty"`
+	// Error related to the key, like `not_found`, only with Keys
+	Error string `json:"error,omitempty"`
+}
+
+// AllDocsIterator is delegated to iterate over the rows of `_all_docs`, requesting them one page at time.
+// The rows are decoded while they are received, so only the current one is kept in memory.
+// Every page is requested with `limit` equal to the page size plus one: the additional row is used as
+// `startkey`/`startkey_docid` of the next page. It is not safe for concurrent use
+//
+//	it := client.AllDocs("db", cloudant.AllDocsOptions{IncludeDocs: true})
+//	defer it.Close()
+//	for it.Next() {
+//		fmt.Println(it.Row().ID)
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type AllDocsIterator struct {
+	c      *Client
+	ctx    context.Context
+	dbName string
+	opts   AllDocsOptions
+
+	page     *rowDecoder
+	pageRows int
+	pageSize int
+	// Key and `_id` of the first row of the next page, if any
+	nextKey, nextID string
+	hasNext         bool
+
+	returned  int
+	totalRows int
+	row       AllDocsRow
+	err       error
+	done      bool
+}
+
+// AllDocs is delegated to return an iterator over the documents of the given DB
+// dbName: DB that we want to retrieve the documents
+// opts: filters and paging of the rows
+func (c *Client) AllDocs(dbName string, opts AllDocsOptions) *AllDocsIterator {
+	return c.AllDocsContext(context.Background(), dbName, opts)
+}
+
+// AllDocsContext is the same as AllDocs, but the requests are bound to the given context
+func (c *Client) AllDocsContext(ctx context.Context, dbName string, opts AllDocsOptions) *AllDocsIterator {
+	if opts.PageSize <= 0 {
+		opts.PageSize = defaultPageSize
+	}
+	it := &AllDocsIterator{c: c, ctx: ctx, dbName: dbName, opts: opts}
+	if dbName == "" {
+		it.err = fmt.Errorf("%w: DB name not provided", ErrInvalidArgument)
+	}
+	return it
+}
+
+// paging return true if the rows are requested in multiple pages
+func (it *AllDocsIterator) paging() bool {
+	return it.opts.Keys == nil
+}
+
+// Next is delegated to advance to the next row, requesting a new page when the current one is consumed.
+// Return false when there are no more rows or an error occurs (see Err)
+func (it *AllDocsIterator) Next() bool {
+	for {
+		if it.err != nil || it.done {
+			return false
+		}
+		if it.opts.Limit > 0 && it.returned >= it.opts.Limit {
+			it.Close()
+			return false
+		}
+		if it.page == nil {
+			if it.err = it.openPage(); it.err != nil {
+				return false
+			}
+		}
+		var row AllDocsRow
+		ok, err := it.page.next(&row)
+		if err != nil {
+			it.err = err
+			it.page.close()
+			return false
+		}
+		if !ok {
+			it.page = nil
+			it.done = !it.hasNext
+			continue
+		}
+		it.pageRows++
+		if it.paging() && it.pageRows > it.pageSize {
+			// The additional row is the first of the next page
+			it.nextKey, it.nextID, it.hasNext = row.Key, row.ID, true
+			it.page.close()
+			it.page = nil
+			continue
+		}
+		it.row = row
+		it.returned++
+		return true
+	}
+}
+
+// openPage is delegated to request the next page of rows
+func (it *AllDocsIterator) openPage() error {
+	vo := it.opts.viewOptions()
+	if it.paging() {
+		it.pageSize = it.opts.PageSize
+		if remaining := it.opts.Limit - it.returned; it.opts.Limit > 0 && remaining < it.pageSize {
+			it.pageSize = remaining
+		}
+		vo.Limit = it.pageSize + 1
+		if it.hasNext {
+			// Skip is alredy applied by the first page
+			vo.StartKey, vo.StartKeyDocID, vo.Skip = it.nextKey, it.nextID, 0
+		}
+	} else {
+		vo.Limit = it.opts.Limit
+	}
+	it.pageRows, it.hasNext = 0, false
+	method, URL, body, err := queryRequest(it.c.databaseURL(it.dbName)+`/_all_docs`, vo)
+	if err != nil {
+		return err
+	}
+	zap.S().Debug("AllDocs | Sending request to URL: [", URL, "]")
+	headers := newHeader(`Accept`, `application/json`, `Content-Type`, `application/json`)
+	res, err := it.c.stream(it.ctx, method, URL, headers, body)
+	if err != nil {
+		return err
+	}
+	if it.page, err = newRowDecoder(res.Body, "rows"); err != nil {
+		return err
+	}
+	return it.page.decodeMeta("total_rows", &it.totalRows)
+}
+
+// Row is delegated to return the current row
+func (it *AllDocsIterator) Row() AllDocsRow {
+	return it.row
+}
+
+// Doc is delegated to return the content of the current document, only with IncludeDocs
+func (it *AllDocsIterator) Doc() json.RawMessage {
+	return it.row.Doc
+}
+
+// Decode is delegated to decode the content of the current document into v, only with IncludeDocs
+func (it *AllDocsIterator) Decode(v interface{}) error {
+	if len(it.row.Doc) == 0 {
+		return fmt.Errorf("cloudant: document %s not included in the row", it.row.ID)
+	}
+	return json.Unmarshal(it.row.Doc, v)
+}
+
+// TotalRows is delegated to return the number of documents in the DB, available after the first call to Next
+func (it *AllDocsIterator) TotalRows() int {
+	return it.totalRows
+}
+
+// Err is delegated to return the error occurred during the iteration, if any
+func (it *AllDocsIterator) Err() error {
+	return it.err
+}
+
+// Close is delegated to stop the iteration, releasing the connection in use.
+// It is safe to call it multiple times
+func (it *AllDocsIterator) Close() error {
+	it.done = true
+	if it.page != nil {
+		it.page.close()
+		it.page = nil
+	}
+	return nil
+}
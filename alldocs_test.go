@@ -0,0 +1,123 @@
+package cloudant
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// newTestAllDocsServer is delegated to initialize a fake instance that serve `_all_docs` for the given documents.
+// Every request is counted in requests
+func newTestAllDocsServer(ids []string, requests *int) *httptest.Server {
+	sort.Strings(ids)
+	return newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/test_db/_all_docs" {
+			w.WriteHeader(404)
+			return
+		}
+		*requests++
+		q := r.URL.Query()
+		rows := make([]string, 0)
+		row := func(id string) string {
+			doc := ""
+			if q.Get("include_docs") == "true" {
+				doc = fmt.Sprintf(`,"doc":{"_id":%q,"_rev":"1-x"}`, id)
+			}
+			return fmt.Sprintf(`{"id":%q,"key":%q,"value":{"rev":"1-x"}%s}`, id, id, doc)
+		}
+		if keys := q.Get("keys"); keys != "" {
+			var requested []string
+			json.Unmarshal([]byte(keys), &requested)
+			for _, key := range requested {
+				if i := sort.SearchStrings(ids, key); i < len(ids) && ids[i] == key {
+					rows = append(rows, row(key))
+				} else {
+					rows = append(rows, fmt.Sprintf(`{"key":%q,"error":"not_found"}`, key))
+				}
+			}
+		} else {
+			ordered := append([]string{}, ids...)
+			if q.Get("descending") == "true" {
+				sort.Sort(sort.Reverse(sort.StringSlice(ordered)))
+			}
+			var start, end string
+			json.Unmarshal([]byte(q.Get("startkey")), &start)
+			json.Unmarshal([]byte(q.Get("endkey")), &end)
+			limit, _ := strconv.Atoi(q.Get("limit"))
+			for _, id := range ordered {
+				before := id < start
+				after := end != "" && id > end
+				if q.Get("descending") == "true" {
+					before = start != "" && id > start
+					after = id < end
+				}
+				if before || after || (limit > 0 && len(rows) >= limit) {
+					continue
+				}
+				rows = append(rows, row(id))
+			}
+		}
+		fmt.Fprintf(w, `{"total_rows":%d,"offset":0,"rows":[%s]}`, len(ids), strings.Join(rows, ","))
+	})
+}
+
+func TestAllDocs(t *testing.T) {
+	ids := make([]string, 25)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("doc%02d", i)
+	}
+	requests := 0
+	srv := newTestAllDocsServer(ids, &requests)
+	defer srv.Close()
+	c := newTestClient(srv)
+
+	it := c.AllDocs("test_db", AllDocsOptions{IncludeDocs: true, PageSize: 10})
+	var found []string
+	for it.Next() {
+		var doc Document
+		if err := it.Decode(&doc); err != nil || doc.ID != it.Row().ID {
+			t.Error("Unexpected document ", doc, err)
+		}
+		found = append(found, it.Row().ID)
+	}
+	if it.Err() != nil || strings.Join(found, ",") != strings.Join(ids, ",") || requests != 3 || it.TotalRows() != 25 {
+		t.Error("Unexpected rows ", found, it.Err(), requests)
+	}
+
+	requests = 0
+	it = c.AllDocs("test_db", AllDocsOptions{Descending: true, StartKey: "doc20", EndKey: "doc05", Limit: 12, PageSize: 5})
+	found = nil
+	for it.Next() {
+		found = append(found, it.Row().ID)
+	}
+	if it.Err() != nil || len(found) != 12 || found[0] != "doc20" || found[11] != "doc09" || requests != 3 {
+		t.Error("Unexpected rows in descending order ", found, it.Err(), requests)
+	}
+	if it.Row().Doc != nil {
+		t.Error("Expected documents not included")
+	}
+
+	it = c.AllDocs("test_db", AllDocsOptions{Keys: []string{"doc03", "missing"}})
+	defer it.Close()
+	if !it.Next() || it.Row().ID != "doc03" || !it.Next() || it.Row().Error != "not_found" || it.Next() || it.Err() != nil {
+		t.Error("Unexpected rows for keys ", it.Row(), it.Err())
+	}
+}
+
+func TestAllDocsError(t *testing.T) {
+	requests := 0
+	srv := newTestAllDocsServer(nil, &requests)
+	defer srv.Close()
+	c := newTestClient(srv)
+	if it := c.AllDocs("missing_db", AllDocsOptions{}); it.Next() || !IsNotFound(it.Err()) {
+		t.Error("Expected not found, got ", it.Err())
+	}
+	if it := c.AllDocs("test_db", AllDocsOptions{}); it.Next() || it.Err() != nil || requests != 1 {
+		t.Error("Expected empty DB, got ", it.Err())
+	}
+}
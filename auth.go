@@ -0,0 +1,169 @@
+package cloudant
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// Authentication types supported by Conf.AuthType, the names are the same used by the IBM Cloud SDK
+const (
+	// AuthTypeIAM authenticate the requests using the IAM token retrieved from the apikey
+	AuthTypeIAM = "iam"
+	// AuthTypeCookie authenticate the requests using the Cloudant session cookie
+	AuthTypeCookie = "couchdb_session"
+	// AuthTypeBasic authenticate the requests using the username and password as basic auth
+	AuthTypeBasic = "basic"
+	// AuthTypeNoAuth does not authenticate the requests, useful for a local CouchDB
+	AuthTypeNoAuth = "noauth"
+)
+
+// Authenticator is delegated to decorate every request sent to Cloudant with the credentials
+type Authenticator interface {
+	// Authenticate is delegated to add the credentials to the given request.
+	// The context of the request have to be used for any call needed for retrieve the credentials
+	Authenticate(req *http.Request) error
+}
+
+// Invalidator is implemented by the Authenticator that use credentials that can be renewed.
+// When Cloudant reject the credentials (401), Invalidate is called and the request is sent again once
+type Invalidator interface {
+	// Invalidate is delegated to discard the current credentials
+	Invalidate()
+}
+
+// AuthenticatorFunc is delegated to use an ordinary function as Authenticator,
+// for example for inject the header requested by a proxy
+type AuthenticatorFunc func(req *http.Request) error
+
+// Authenticate call f(req)
+func (f AuthenticatorFunc) Authenticate(req *http.Request) error {
+	return f(req)
+}
+
+// IAMAuthenticator is delegated to authenticate the requests using the IAM token as 'Bearer Authorization'
+// https://cloud.ibm.com/docs/services/Cloudant?topic=cloudant-managing-access-for-cloudant
+type IAMAuthenticator struct {
+	*IAMTokenSource
+}
+
+// NewIAMAuthenticator is delegated to initialize a new IAM authenticator for the given apikey.
+// tokenURL: IAM endpoint used for retrieve the token, DefaultIAMURL if empty
+// httpClient: client used for request the token, http.DefaultClient if nil
+func NewIAMAuthenticator(apikey, tokenURL string, httpClient *http.Client) *IAMAuthenticator {
+	return &IAMAuthenticator{NewIAMTokenSource(apikey, tokenURL, httpClient)}
+}
+
+// Authenticate is delegated to add the IAM token to the request, refreshing it if necessary
+func (a *IAMAuthenticator) Authenticate(req *http.Request) error {
+	token, err := a.Token(req.Context())
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
+
+// CookieAuthenticator is delegated to authenticate the requests using the Cloudant session cookie
+// https://cloud.ibm.com/docs/services/Cloudant?topic=cloudant-authentication#cookie-authentication
+type CookieAuthenticator struct {
+	*CookieSession
+}
+
+// NewCookieAuthenticator is delegated to initialize a new cookie authenticator for the given Cloudant instance.
+// dbURL: URL related to the Cloudant instance
+// httpClient: client used for initialize the session, http.DefaultClient if nil
+func NewCookieAuthenticator(dbURL, username, password string, httpClient *http.Client) *CookieAuthenticator {
+	return &CookieAuthenticator{NewCookieSession(dbURL, username, password, httpClient)}
+}
+
+// Authenticate is delegated to add the session cookie to the request, renewing the session if necessary
+func (a *CookieAuthenticator) Authenticate(req *http.Request) error {
+	cookie, err := a.Cookie(req.Context())
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Cookie", cookie)
+	return nil
+}
+
+// BasicAuthenticator is delegated to authenticate the requests using the username and password as basic auth.
+// NOTE: Avoid to use the basic auth with Cloudant for performance, every request have to verify the password
+type BasicAuthenticator struct {
+	Username string
+	Password string
+}
+
+// Authenticate is delegated to add the basic auth header to the request
+func (a BasicAuthenticator) Authenticate(req *http.Request) error {
+	rawHeaders := a.Username + `:` + a.Password
+	req.Header.Set("Authorization", `Basic `+base64.StdEncoding.EncodeToString([]byte(rawHeaders)))
+	return nil
+}
+
+// NoAuthAuthenticator is delegated to send the requests without any credentials, for example to a local CouchDB
+type NoAuthAuthenticator struct{}
+
+// Authenticate does nothing
+func (NoAuthAuthenticator) Authenticate(req *http.Request) error {
+	return nil
+}
+
+// authType is delegated to resolve the authentication described by the configuration, verifying that the
+// related credentials are provided.
+// When the AuthType is not provided, IAM is used if the apikey is present, otherwise the session cookie
+func (conf Conf) authType() (string, error) {
+	authType := strings.ToLower(strings.TrimSpace(conf.AuthType))
+	if authType == "" {
+		if conf.Apikey != "" {
+			authType = AuthTypeIAM
+		} else if conf.Username != "" && conf.Password != "" {
+			authType = AuthTypeCookie
+		}
+	}
+	switch authType {
+	case AuthTypeIAM:
+		if conf.Apikey == "" {
+			return "", fmt.Errorf("%w: apikey not provided", ErrInvalidArgument)
+		}
+	case AuthTypeCookie, AuthTypeBasic:
+		if conf.Username == "" || conf.Password == "" {
+			return "", fmt.Errorf("%w: username or password not provided", ErrInvalidArgument)
+		}
+	case AuthTypeNoAuth:
+	case "":
+		return "", fmt.Errorf("%w: neither apikey nor username and password provided", ErrInvalidArgument)
+	default:
+		return "", fmt.Errorf("%w: authentication type [%s] not supported", ErrInvalidArgument, conf.AuthType)
+	}
+	return authType, nil
+}
+
+// newAuthenticator is delegated to initialize the Authenticator described by the configuration
+// dbURL: URL related to the Cloudant instance
+func newAuthenticator(conf Conf, dbURL string, httpClient *http.Client) (Authenticator, error) {
+	authType, err := conf.authType()
+	if err != nil {
+		zap.S().Error("newAuthenticator | Unable to retrieve credentials from configuration | Err: ", err)
+		return nil, err
+	}
+	zap.S().Debug("newAuthenticator | Initializing authentication [", authType, "]")
+	switch authType {
+	case AuthTypeIAM:
+		return NewIAMAuthenticator(conf.Apikey, conf.IAMURL, httpClient), nil
+	case AuthTypeCookie:
+		return NewCookieAuthenticator(dbURL, conf.Username, conf.Password, httpClient), nil
+	case AuthTypeBasic:
+		return BasicAuthenticator{Username: conf.Username, Password: conf.Password}, nil
+	}
+	return NoAuthAuthenticator{}, nil
+}
+
+// logouter is implemented by the Authenticator that keep a session open server side
+type logouter interface {
+	Logout(ctx context.Context) error
+}
@@ -0,0 +1,84 @@
+package cloudant
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestNewAuthenticator(t *testing.T) {
+	cases := []struct {
+		conf     Conf
+		expected string
+	}{
+		{Conf{Apikey: "apikey", Username: "user", Password: "pass"}, "*cloudant.IAMAuthenticator"},
+		{Conf{Username: "user", Password: "pass"}, "*cloudant.CookieAuthenticator"},
+		{Conf{Username: "user", Password: "pass", AuthType: "BASIC"}, "cloudant.BasicAuthenticator"},
+		{Conf{AuthType: AuthTypeNoAuth}, "cloudant.NoAuthAuthenticator"},
+	}
+	for _, c := range cases {
+		auth, err := newAuthenticator(c.conf, "https://localhost", nil)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if got := fmt.Sprintf("%T", auth); got != c.expected {
+			t.Error("Expected ", c.expected, " got ", got)
+		}
+	}
+	for _, conf := range []Conf{{}, {AuthType: AuthTypeIAM}, {AuthType: AuthTypeCookie, Username: "user"}, {AuthType: "oauth"}} {
+		if _, err := newAuthenticator(conf, "https://localhost", nil); !errors.Is(err, ErrInvalidArgument) {
+			t.Error("Expected invalid argument for ", conf, " got ", err)
+		}
+	}
+}
+
+func TestBasicAuthenticator(t *testing.T) {
+	srv := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); !ok || user != "user" || pass != "pass" {
+			w.WriteHeader(401)
+			return
+		}
+		w.Write([]byte(`{"couchdb":"Welcome"}`))
+	})
+	defer srv.Close()
+	conf := Conf{Host: strings.TrimPrefix(srv.URL, "https://"), Username: "user", Password: "pass", AuthType: AuthTypeBasic}
+	c, err := NewClient(conf, WithHTTPClient(srv.Client()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = c.PingCloudant(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestCustomAuthenticator(t *testing.T) {
+	srv := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Proxy-Token") != "secret" || r.Header.Get("Authorization") != "" {
+			w.WriteHeader(401)
+			return
+		}
+		w.Write([]byte(`{"couchdb":"Welcome"}`))
+	})
+	defer srv.Close()
+	proxy := AuthenticatorFunc(func(req *http.Request) error {
+		req.Header.Set("X-Proxy-Token", "secret")
+		return nil
+	})
+	// The credentials in the configuration are ignored when an Authenticator is provided
+	conf := Conf{Host: strings.TrimPrefix(srv.URL, "https://"), Apikey: "apikey"}
+	c, err := NewClient(conf, WithHTTPClient(srv.Client()), WithAuthenticator(proxy))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = c.PingCloudant(); err != nil {
+		t.Error(err)
+	}
+	failing := AuthenticatorFunc(func(req *http.Request) error { return errors.New("proxy not reachable") })
+	c, _ = NewClient(conf, WithHTTPClient(srv.Client()), WithAuthenticator(failing))
+	if err = c.PingCloudant(); err == nil || err.Error() != "proxy not reachable" {
+		t.Error("Expected authenticator error, got ", err)
+	}
+}
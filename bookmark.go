@@ -0,0 +1,176 @@
+package cloudant
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"go.uber.org/zap"
+)
+
+// defaultBookmarkPageSize is the number of results requested for every page, when not set in the options
+const defaultBookmarkPageSize = 200
+
+// openPageFunc is delegated to request the page of results that starts from the given bookmark.
+// The body of the returned response have to contain the results and the bookmark of the next page
+type openPageFunc func(ctx context.Context, bookmark string) (*http.Response, error)
+
+// BookmarkIterator is delegated to iterate over the results of a query paginated with the Cloudant `bookmark`,
+// like `_find` and search. The results are decoded while they are received, so only the current one is kept in memory.
+// The iteration stops when a page contains less results than the page size, or the context is cancelled.
+// It is not safe for concurrent use
+//
+//	it := client.FindIterator("db", cloudant.Field("type").Eq("user"), cloudant.FindOptions{})
+//	defer it.Close()
+//	for it.Next() {
+//		var user User
+//		if err := it.Decode(&user); err != nil {
+//			...
+//		}
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type BookmarkIterator struct {
+	ctx      context.Context
+	name     string
+	open     openPageFunc
+	field    string
+	pageSize int
+
+	// Bookmark used for request the current page
+	bookmark string
+	page     *rowDecoder
+	pageRows int
+	row      json.RawMessage
+	err      error
+	done     bool
+}
+
+// newBookmarkIterator is delegated to initialize an iterator over the elements of field, starting from the given bookmark
+func newBookmarkIterator(ctx context.Context, name, field, bookmark string, pageSize int, open openPageFunc) *BookmarkIterator {
+	return &BookmarkIterator{ctx: ctx, name: name, open: open, field: field, pageSize: pageSize, bookmark: bookmark}
+}
+
+// Next is delegated to advance to the next result, requesting a new page when the current one is consumed.
+// Return false when there are no more results or an error occurs (see Err)
+func (it *BookmarkIterator) Next() bool {
+	for {
+		if it.err != nil || it.done {
+			return false
+		}
+		if it.err = it.ctx.Err(); it.err != nil {
+			it.Close()
+			return false
+		}
+		if it.page == nil {
+			zap.S().Debug(it.name, " | Requesting page from bookmark [", it.bookmark, "]")
+			res, err := it.open(it.ctx, it.bookmark)
+			if err != nil {
+				it.err = err
+				return false
+			}
+			if it.page, it.err = newRowDecoder(res.Body, it.field); it.err != nil {
+				return false
+			}
+			it.pageRows = 0
+		}
+		var row json.RawMessage
+		ok, err := it.page.next(&row)
+		if err != nil {
+			it.err = err
+			it.Close()
+			return false
+		}
+		if ok {
+			it.pageRows++
+			it.row = row
+			return true
+		}
+		var next string
+		if it.err = it.page.decodeMeta("bookmark", &next); it.err != nil {
+			return false
+		}
+		it.page = nil
+		if it.pageRows < it.pageSize || next == "" || next == it.bookmark {
+			it.done = true
+		}
+		if next != "" {
+			it.bookmark = next
+		}
+	}
+}
+
+// Row is delegated to return the current result as received from Cloudant
+func (it *BookmarkIterator) Row() json.RawMessage {
+	return it.row
+}
+
+// Decode is delegated to decode the current result into v
+func (it *BookmarkIterator) Decode(v interface{}) error {
+	if it.row == nil {
+		return fmt.Errorf("cloudant: no result available, Next have to be called before Decode")
+	}
+	return json.Unmarshal(it.row, v)
+}
+
+// Bookmark is delegated to return the bookmark that can be used for resume the iteration later (ex: FindOptions.Bookmark).
+// It points to the beginning of the current page, so the results of the page not yet consumed are not lost
+// but the ones alredy returned can be returned again. When the current page is consumed, it points to the next one
+func (it *BookmarkIterator) Bookmark() string {
+	return it.bookmark
+}
+
+// Err is delegated to return the error occurred during the iteration, if any
+func (it *BookmarkIterator) Err() error {
+	return it.err
+}
+
+// Close is delegated to stop the iteration, releasing the connection in use.
+// It is safe to call it multiple times
+func (it *BookmarkIterator) Close() error {
+	it.done = true
+	if it.page != nil {
+		it.page.close()
+		it.page = nil
+	}
+	return nil
+}
+
+// FindIterator is delegated to iterate over all the documents that match the given selector, following the bookmarks.
+// The Limit of the options is used as page size (200 if not set), and the Bookmark as starting point
+// dbName: DB that we want to query
+// selector: condition that the documents have to match, see Field for build it
+// opts: fields, sort and paging of the results
+func (c *Client) FindIterator(dbName string, selector Selector, opts FindOptions) *BookmarkIterator {
+	return c.FindIteratorContext(context.Background(), dbName, selector, opts)
+}
+
+// FindIteratorContext is the same as FindIterator, but the requests are bound to the given context.
+// The iteration stops as soon as the context is cancelled
+func (c *Client) FindIteratorContext(ctx context.Context, dbName string, selector Selector, opts FindOptions) *BookmarkIterator {
+	if opts.Limit <= 0 {
+		opts.Limit = defaultBookmarkPageSize
+	}
+	URL := c.findURL(dbName, opts.Partition, `_find`)
+	headers := newHeader(`Accept`, `application/json`, `Content-Type`, `application/json`)
+	first := true
+	return newBookmarkIterator(ctx, "FindIterator", "docs", opts.Bookmark, opts.Limit, func(ctx context.Context, bookmark string) (*http.Response, error) {
+		if dbName == "" {
+			return nil, fmt.Errorf("%w: DB name not provided", ErrInvalidArgument)
+		}
+		req := newFindRequest(selector, opts)
+		req.Bookmark = bookmark
+		if !first {
+			// Skip is alredy applied by the first page
+			req.Skip = 0
+		}
+		first = false
+		body, err := json.Marshal(req)
+		if err != nil {
+			return nil, fmt.Errorf("%w: unable to encode query: %v", ErrInvalidArgument, err)
+		}
+		return c.stream(ctx, `POST`, URL, headers, body)
+	})
+}
@@ -0,0 +1,90 @@
+package cloudant
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// newTestFindServer is delegated to initialize a fake instance that return the given number of documents from `_find`.
+// The bookmark is the position of the next document
+func newTestFindServer(total int, requests *int) *httptest.Server {
+	return newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Limit    int    `json:"limit"`
+			Bookmark string `json:"bookmark"`
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		if r.URL.Path != "/test_db/_find" || json.Unmarshal(body, &req) != nil {
+			w.WriteHeader(400)
+			return
+		}
+		*requests++
+		start, _ := strconv.Atoi(req.Bookmark)
+		var docs []string
+		for i := start; i < total && len(docs) < req.Limit; i++ {
+			docs = append(docs, fmt.Sprintf(`{"_id":"%d"}`, i))
+		}
+		fmt.Fprintf(w, `{"docs":[%s],"bookmark":"%d"}`, strings.Join(docs, ","), start+len(docs))
+	})
+}
+
+func TestFindIterator(t *testing.T) {
+	requests := 0
+	srv := newTestFindServer(25, &requests)
+	defer srv.Close()
+	c := newTestClient(srv)
+
+	it := c.FindIterator("test_db", Field("_id").Gt(nil), FindOptions{Limit: 10})
+	var ids []string
+	for it.Next() {
+		var doc Document
+		if err := it.Decode(&doc); err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, doc.ID)
+	}
+	if it.Err() != nil || len(ids) != 25 || ids[24] != "24" || requests != 3 || it.Bookmark() != "25" {
+		t.Error("Unexpected results ", ids, it.Err(), requests, it.Bookmark())
+	}
+
+	// Stop in the middle of the second page, and resume from the checkpoint
+	it = c.FindIterator("test_db", nil, FindOptions{Limit: 10})
+	for i := 0; i < 15 && it.Next(); i++ {
+	}
+	checkpoint := it.Bookmark()
+	it.Close()
+	if checkpoint != "10" || it.Next() {
+		t.Fatal("Expected checkpoint at the beginning of the second page, got ", checkpoint)
+	}
+	it = c.FindIterator("test_db", nil, FindOptions{Limit: 10, Bookmark: checkpoint})
+	defer it.Close()
+	if !it.Next() || string(it.Row()) != `{"_id":"10"}` {
+		t.Error("Expected iteration resumed from the checkpoint, got ", string(it.Row()), it.Err())
+	}
+}
+
+func TestFindIteratorContext(t *testing.T) {
+	requests := 0
+	srv := newTestFindServer(25, &requests)
+	defer srv.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	it := newTestClient(srv).FindIteratorContext(ctx, "test_db", nil, FindOptions{Limit: 10})
+	if !it.Next() {
+		t.Fatal(it.Err())
+	}
+	cancel()
+	if it.Next() || !errors.Is(it.Err(), context.Canceled) {
+		t.Error("Expected iteration stopped by the context, got ", it.Err())
+	}
+	if it = newTestClient(srv).FindIterator("missing_db", nil, FindOptions{}); it.Next() || !IsBadRequest(it.Err()) {
+		t.Error("Expected bad request, got ", it.Err())
+	}
+}
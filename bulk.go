@@ -0,0 +1,392 @@
+package cloudant
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+// BulkResult is delegated to save the outcome of a single document sent with `_bulk_docs`
+// https://cloud.ibm.com/docs/services/Cloudant?topic=cloudant-documents#bulk-operations
+type BulkResult struct {
+	// Position of the document in the list sent to BulkDocs
+	Index int `json:"-"`
+	// Key of the document
+	ID string `json:"id"`
+	// New revision of the document, empty if the write failed
+	Rev string `json:"rev,omitempty"`
+	// Error returned for the document (ex: conflict, forbidden), or NotSent when the request was never completed
+	Error string `json:"error,omitempty"`
+	// Reason of the error
+	Reason string `json:"reason,omitempty"`
+}
+
+// NotSent is the error of the documents that BulkDocsBatched did not write because their request failed or was aborted
+const NotSent = "not_sent"
+
+// Failed return true if the document was not written
+func (r BulkResult) Failed() bool {
+	return r.Error != ""
+}
+
+// IsConflict return true if the document was not written because the `_rev` is not the latest
+func (r BulkResult) IsConflict() bool {
+	return r.Error == "conflict"
+}
+
+// Err is delegated to return the failure of the document as error, nil if the document was written
+func (r BulkResult) Err() error {
+	if !r.Failed() {
+		return nil
+	}
+	return fmt.Errorf("cloudant: document %s not written: %s: %s", r.ID, r.Error, r.Reason)
+}
+
+// BulkResults is the list of the outcome of a `_bulk_docs` request, in the same order of the documents sent
+type BulkResults []BulkResult
+
+// Failures is delegated to return the results of the documents that were not written
+func (results BulkResults) Failures() BulkResults {
+	return results.filter(BulkResult.Failed)
+}
+
+// Conflicts is delegated to return the results of the documents that were not written due to a conflict
+func (results BulkResults) Conflicts() BulkResults {
+	return results.filter(BulkResult.IsConflict)
+}
+
+func (results BulkResults) filter(keep func(BulkResult) bool) BulkResults {
+	var filtered BulkResults
+	for _, r := range results {
+		if keep(r) {
+			filtered = append(filtered, r)
+		}
+	}
+	return filtered
+}
+
+// bulkMeta is delegated to return the metadata of an element of a bulk request, for both T and *T that embed Document
+func bulkMeta[T any](doc *T) *Document {
+	if m := meta(doc); m != nil {
+		return m
+	}
+	return meta(*doc)
+}
+
+// BulkDocs is delegated to insert, update or delete a list of documents in a single request.
+// []byte, json.RawMessage and string are considered alredy encoded, any other value is encoded with encoding/json;
+// the ones that embed Document receive back the new `_rev`. A document can be deleted setting Deleted to true.
+// A document greater than 1MB is rejected with ErrDocumentTooLarge before sending the request.
+// A successful request does not imply that every document was written: inspect the returned results (see Failures)
+// dbName: DB that we want to use for store the documents
+// docs: list of documents
+func BulkDocs[T any](c *Client, dbName string, docs []T) (BulkResults, error) {
+	return BulkDocsContext(context.Background(), c, dbName, docs)
+}
+
+// BulkDocsContext is the same as BulkDocs, but the request is bound to the given context
+func BulkDocsContext[T any](ctx context.Context, c *Client, dbName string, docs []T) (BulkResults, error) {
+	zap.S().Debug("BulkDocs | Inserting ", len(docs), " in bulk into [", dbName, "] ...")
+	if dbName == "" {
+		return nil, fmt.Errorf("%w: DB name is mandatory", ErrInvalidArgument)
+	}
+	if len(docs) == 0 {
+		return BulkResults{}, nil
+	}
+	raw := make([]json.RawMessage, len(docs))
+	for i := range docs {
+		data, err := marshalDocument(docs[i])
+		if err != nil {
+			return nil, fmt.Errorf("cloudant: document %d: %w", i, err)
+		}
+		raw[i] = data
+	}
+	body, err := json.Marshal(struct {
+		Docs []json.RawMessage `json:"docs"`
+	}{raw})
+	if err != nil {
+		return nil, fmt.Errorf("cloudant: unable to encode documents: %w", err)
+	}
+	url := c.databaseURL(dbName) + `/_bulk_docs`
+	headers := newHeader(`Accept`, `application/json`, `Content-Type`, `application/json`)
+	zap.S().Debug("BulkDocs | Sending request to URL: [", url, "]")
+	response, err := c.send(ctx, `POST`, url, headers, body)
+	zap.S().Debug("BulkDocs | Request executed -> Status: [", response.StatusCode, "]")
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode == 202 {
+		zap.S().Warn("BulkDocs | WARNING! Update does not meet the quorum")
+	}
+	var results BulkResults
+	if err = json.Unmarshal(response.Body, &results); err != nil {
+		return nil, fmt.Errorf("cloudant: unable to decode response: %w", err)
+	}
+	if len(results) != len(docs) {
+		return results, fmt.Errorf("cloudant: %d results received for %d documents", len(results), len(docs))
+	}
+	for i := range results {
+		results[i].Index = i
+		if m := bulkMeta(&docs[i]); m != nil && !results[i].Failed() {
+			m.ID, m.Rev = results[i].ID, results[i].Rev
+		}
+	}
+	if failures := results.Failures(); len(failures) > 0 {
+		zap.S().Warn("BulkDocs | ", len(failures), " documents were not written")
+	}
+	return results, nil
+}
+
+// RetryBulkDocs is delegated to send again the documents that failed in a previous BulkDocs call.
+// For every failed document resolve is called, it can modify the document and return false for skip it.
+// When resolve is nil, the documents in conflict that embed Document are retried with the latest `_rev`
+// available on the server (the last write wins), the other failures are sent as is.
+// Return the given results updated with the outcome of the retried documents
+// dbName: DB that we want to use for store the documents
+// docs: the same list of documents sent to BulkDocs
+// results: the results returned by BulkDocs
+func RetryBulkDocs[T any](c *Client, dbName string, docs []T, results BulkResults, resolve func(doc *T, result BulkResult) bool) (BulkResults, error) {
+	return RetryBulkDocsContext(context.Background(), c, dbName, docs, results, resolve)
+}
+
+// RetryBulkDocsContext is the same as RetryBulkDocs, but the requests are bound to the given context
+func RetryBulkDocsContext[T any](ctx context.Context, c *Client, dbName string, docs []T, results BulkResults, resolve func(doc *T, result BulkResult) bool) (BulkResults, error) {
+	if len(results) != len(docs) {
+		return results, fmt.Errorf("%w: %d results for %d documents", ErrInvalidArgument, len(results), len(docs))
+	}
+	var retry []T
+	var indexes []int
+	for _, r := range results.Failures() {
+		doc := &docs[r.Index]
+		if resolve != nil {
+			if !resolve(doc, r) {
+				continue
+			}
+		} else if m := bulkMeta(doc); r.IsConflict() && m != nil && m.ID != "" {
+			rev, err := c.GetRevisionContext(ctx, dbName, m.ID)
+			if err != nil {
+				return results, err
+			}
+			m.Rev = rev
+		}
+		retry = append(retry, *doc)
+		indexes = append(indexes, r.Index)
+	}
+	if len(retry) == 0 {
+		return results, nil
+	}
+	zap.S().Debug("RetryBulkDocs | Retrying ", len(retry), " documents")
+	retried, err := BulkDocsContext(ctx, c, dbName, retry)
+	if err != nil {
+		return results, err
+	}
+	updated := make(BulkResults, len(results))
+	copy(updated, results)
+	for i, r := range retried {
+		r.Index = indexes[i]
+		updated[r.Index] = r
+		docs[r.Index] = retry[i]
+	}
+	return updated, nil
+}
+
+// GetRevision is delegated to retrieve the latest revision of a document, without download the document
+// dbName: DB that contains the document
+// _id: Key of the document
+func (c *Client) GetRevision(dbName, _id string) (string, error) {
+	return c.GetRevisionContext(context.Background(), dbName, _id)
+}
+
+// GetRevisionContext is the same as GetRevision, but the request is bound to the given context
+func (c *Client) GetRevisionContext(ctx context.Context, dbName, _id string) (string, error) {
+	zap.S().Debug("GetRevision | Retrieving revision of [", _id, "] from DB [", dbName, "]")
+	if dbName == "" || _id == "" {
+		return "", fmt.Errorf("%w: DB name and '_id' are mandatory", ErrInvalidArgument)
+	}
+	response, err := c.send(ctx, `HEAD`, c.docURL(dbName, _id), http.Header{}, nil)
+	if err != nil {
+		return "", err
+	}
+	etag := response.Header.Get("ETag")
+	if len(etag) >= 2 && etag[0] == '"' && etag[len(etag)-1] == '"' {
+		etag = etag[1 : len(etag)-1]
+	}
+	return etag, nil
+}
+
+// maxRequestSize is the maximum size of a request accepted by Cloudant
+const maxRequestSize = 10485760
+
+// BatchOptions is delegated to customize how BulkDocsBatched split and send the documents
+type BatchOptions struct {
+	// Maximum number of documents for every request, 500 if not set
+	BatchSize int
+	// Maximum size in bytes of every request, 10MB (the Cloudant limit) if not set or greater
+	MaxBatchBytes int
+	// Number of requests sent at the same time, 4 if not set
+	Concurrency int
+	// Called after every batch is completed, never concurrently
+	OnProgress func(BatchProgress)
+}
+
+// BatchProgress is delegated to save the progress of BulkDocsBatched
+type BatchProgress struct {
+	// Number of batches to send
+	Batches int
+	// Number of batches alredy sent
+	Completed int
+	// Number of documents written
+	Written int
+	// Number of documents rejected by Cloudant
+	Failed int
+}
+
+// withDefaults is delegated to fill the options that are not set
+func (opts BatchOptions) withDefaults() BatchOptions {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 500
+	}
+	if opts.MaxBatchBytes <= 0 || opts.MaxBatchBytes > maxRequestSize {
+		opts.MaxBatchBytes = maxRequestSize
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 4
+	}
+	return opts
+}
+
+// splitBatches is delegated to group the encoded documents in batches that respect the count and the size limits.
+// Return the indexes of the documents of every batch
+func splitBatches(encoded [][]byte, batchSize, maxBytes int) [][]int {
+	// {"docs":[]} plus the comma between the documents
+	const overhead = len(`{"docs":[]}`)
+	var batches [][]int
+	var current []int
+	size := overhead
+	for i, doc := range encoded {
+		if len(current) > 0 && (len(current) >= batchSize || size+len(doc)+1 > maxBytes) {
+			batches = append(batches, current)
+			current, size = nil, overhead
+		}
+		current = append(current, i)
+		size += len(doc) + 1
+	}
+	if len(current) > 0 {
+		batches = append(batches, current)
+	}
+	return batches
+}
+
+// BulkDocsBatched is delegated to write a large list of documents, splitting them in multiple `_bulk_docs` requests
+// sent concurrently. Every request respect the count and the size set in the options; a document greater than 1MB is
+// rejected with ErrDocumentTooLarge before sending anything.
+// The results are in the same order of the documents, the ones that embed Document receive back the new `_rev`.
+// When a request fails the remaining ones are aborted and the error is returned: the documents of the failed and of the
+// aborted requests are reported in the results as failures with the NotSent error, so they can be sent again
+// dbName: DB that we want to use for store the documents
+// docs: list of documents
+func BulkDocsBatched[T any](c *Client, dbName string, docs []T, opts BatchOptions) (BulkResults, error) {
+	return BulkDocsBatchedContext(context.Background(), c, dbName, docs, opts)
+}
+
+// BulkDocsBatchedContext is the same as BulkDocsBatched, but the requests are bound to the given context
+func BulkDocsBatchedContext[T any](ctx context.Context, c *Client, dbName string, docs []T, opts BatchOptions) (BulkResults, error) {
+	opts = opts.withDefaults()
+	encoded := make([][]byte, len(docs))
+	for i := range docs {
+		var err error
+		if encoded[i], err = marshalDocument(docs[i]); err != nil {
+			return nil, fmt.Errorf("cloudant: document %d: %w", i, err)
+		}
+	}
+	batches := splitBatches(encoded, opts.BatchSize, opts.MaxBatchBytes)
+	zap.S().Debug("BulkDocsBatched | Sending ", len(docs), " documents in ", len(batches), " batches into [", dbName, "] ...")
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	results := make(BulkResults, len(docs))
+	// Documents whose request was completed, successfully or not
+	sent := make([]bool, len(docs))
+	progress := BatchProgress{Batches: len(batches)}
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		firstErr error
+	)
+	sem := make(chan struct{}, opts.Concurrency)
+	for _, batch := range batches {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		go func(batch []int) {
+			defer func() { <-sem; wg.Done() }()
+			raw := make([]json.RawMessage, len(batch))
+			for i, idx := range batch {
+				raw[i] = encoded[idx]
+			}
+			batchResults, err := BulkDocsContext(ctx, c, dbName, raw)
+			mu.Lock()
+			defer mu.Unlock()
+			for _, idx := range batch {
+				sent[idx] = true
+			}
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+					cancel()
+				}
+				for _, idx := range batch {
+					results[idx] = notSent(docs, idx, err.Error())
+				}
+				return
+			}
+			for i, r := range batchResults {
+				r.Index = batch[i]
+				results[r.Index] = r
+				if r.Failed() {
+					progress.Failed++
+				} else {
+					progress.Written++
+					if m := bulkMeta(&docs[r.Index]); m != nil {
+						m.ID, m.Rev = r.ID, r.Rev
+					}
+				}
+			}
+			progress.Completed++
+			if opts.OnProgress != nil {
+				opts.OnProgress(progress)
+			}
+		}(batch)
+	}
+	wg.Wait()
+	if firstErr == nil {
+		firstErr = ctx.Err()
+	}
+	if firstErr != nil {
+		zap.S().Error("BulkDocsBatched | ERROR! ", progress.Completed, "/", progress.Batches, " batches sent | Err: ", firstErr)
+		for idx := range results {
+			if !sent[idx] {
+				results[idx] = notSent(docs, idx, "request aborted: "+firstErr.Error())
+			}
+		}
+	}
+	return results, firstErr
+}
+
+// notSent is delegated to compose the result of a document whose request was not completed
+func notSent[T any](docs []T, idx int, reason string) BulkResult {
+	r := BulkResult{Index: idx, Error: NotSent, Reason: reason}
+	if m := bulkMeta(&docs[idx]); m != nil {
+		r.ID = m.ID
+	}
+	return r
+}
@@ -0,0 +1,223 @@
+package cloudant
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+// newTestBulkServer is delegated to initialize a fake instance that store the revision of every document.
+// A document is rejected with a conflict if its `_rev` is not the stored one
+func newTestBulkServer(revs map[string]int) *httptest.Server {
+	return newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "HEAD" {
+			w.Header().Set("ETag", `"`+strconv.Itoa(revs[r.URL.Path[len("/test_db/"):]])+`-x"`)
+			return
+		}
+		var req struct {
+			Docs []testPerson `json:"docs"`
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		if r.URL.Path != "/test_db/_bulk_docs" || json.Unmarshal(body, &req) != nil {
+			w.WriteHeader(400)
+			return
+		}
+		var results []BulkResult
+		for _, doc := range req.Docs {
+			current := strconv.Itoa(revs[doc.ID]) + "-x"
+			if revs[doc.ID] > 0 && doc.Rev != current {
+				results = append(results, BulkResult{ID: doc.ID, Error: "conflict", Reason: "Document update conflict."})
+				continue
+			}
+			revs[doc.ID]++
+			results = append(results, BulkResult{ID: doc.ID, Rev: strconv.Itoa(revs[doc.ID]) + "-x"})
+		}
+		w.WriteHeader(201)
+		json.NewEncoder(w).Encode(results)
+	})
+}
+
+func TestBulkDocs(t *testing.T) {
+	srv := newTestBulkServer(map[string]int{"2": 3})
+	defer srv.Close()
+	c := newTestClient(srv)
+	docs := []testPerson{{Document: Document{ID: "1"}, Name: "a"}, {Document: Document{ID: "2", Rev: "1-x"}, Name: "b"}}
+	results, err := BulkDocs(c, "test_db", docs)
+	if err != nil || len(results) != 2 {
+		t.Fatal("Unexpected results ", results, err)
+	}
+	if results[0].Failed() || docs[0].Rev != "1-x" {
+		t.Error("Expected revision written back, got ", docs[0].Rev)
+	}
+	if conflicts := results.Conflicts(); len(conflicts) != 1 || conflicts[0].Index != 1 || conflicts[0].Err() == nil || docs[1].Rev != "1-x" {
+		t.Error("Expected conflict for the second document, got ", conflicts)
+	}
+	// Pointers are supported as well
+	ptrs := []*testPerson{{Document: Document{ID: "3"}}}
+	if _, err = BulkDocs(c, "test_db", ptrs); err != nil || ptrs[0].Rev != "1-x" {
+		t.Error("Expected revision written back into pointer, got ", ptrs[0].Rev, err)
+	}
+}
+
+func TestBulkDocsEncoded(t *testing.T) {
+	bodies := make(chan string, 2)
+	srv := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		bodies <- string(body)
+		w.WriteHeader(201)
+		w.Write([]byte(`[{"id":"1","rev":"1-x"},{"id":"2","rev":"1-x"}]`))
+	})
+	defer srv.Close()
+	c := newTestClient(srv)
+	// The documents alredy encoded are sent as is, not as base64 or JSON strings
+	if _, err := BulkDocs(c, "test_db", [][]byte{[]byte(`{"_id":"1"}`), []byte(`{"_id":"2"}`)}); err != nil || <-bodies != `{"docs":[{"_id":"1"},{"_id":"2"}]}` {
+		t.Error("Unexpected request for []byte documents ", err)
+	}
+	if _, err := BulkDocs(c, "test_db", []string{`{"_id":"1"}`, `{"_id":"2"}`}); err != nil || <-bodies != `{"docs":[{"_id":"1"},{"_id":"2"}]}` {
+		t.Error("Unexpected request for string documents ", err)
+	}
+	if _, err := BulkDocs(c, "test_db", []string{string(make([]byte, 1048576))}); !errors.Is(err, ErrDocumentTooLarge) {
+		t.Error("Expected error for document bigger than 1MB, got ", err)
+	}
+}
+
+func TestRetryBulkDocs(t *testing.T) {
+	srv := newTestBulkServer(map[string]int{"1": 2, "2": 2})
+	defer srv.Close()
+	c := newTestClient(srv)
+	docs := []testPerson{{Document: Document{ID: "1", Rev: "1-x"}}, {Document: Document{ID: "2", Rev: "1-x"}}}
+	results, err := BulkDocs(c, "test_db", docs)
+	if err != nil || len(results.Conflicts()) != 2 {
+		t.Fatal("Expected conflicts, got ", results, err)
+	}
+	// Skip the first document, retry the second one with the latest revision
+	results, err = RetryBulkDocs(c, "test_db", docs, results, func(doc *testPerson, r BulkResult) bool {
+		if doc.ID == "1" {
+			return false
+		}
+		doc.Rev = "2-x"
+		return true
+	})
+	if err != nil || !results[0].IsConflict() || results[1].Failed() || docs[1].Rev != "3-x" {
+		t.Error("Unexpected results after resolve ", results, err)
+	}
+	// Without resolve the latest revision is retrieved from the server
+	if results, err = RetryBulkDocs(c, "test_db", docs, results, nil); err != nil || len(results.Failures()) != 0 || docs[0].Rev != "3-x" {
+		t.Error("Unexpected results after retry ", results, err)
+	}
+}
+
+func TestSplitBatches(t *testing.T) {
+	encoded := [][]byte{make([]byte, 10), make([]byte, 10), make([]byte, 10), make([]byte, 30)}
+	if batches := splitBatches(encoded, 2, 1000); len(batches) != 2 || len(batches[0]) != 2 || batches[1][0] != 2 {
+		t.Error("Expected batches split by count, got ", batches)
+	}
+	// 11 bytes of overhead plus 11 bytes for every small document
+	if batches := splitBatches(encoded, 10, 40); len(batches) != 3 || len(batches[0]) != 2 || len(batches[1]) != 1 || len(batches[2]) != 1 {
+		t.Error("Expected batches split by size, got ", batches)
+	}
+}
+
+func TestBulkDocsBatched(t *testing.T) {
+	var mu sync.Mutex
+	requests := 0
+	srv := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Docs []testPerson `json:"docs"`
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		if json.Unmarshal(body, &req) != nil || len(req.Docs) > 3 {
+			w.WriteHeader(400)
+			return
+		}
+		mu.Lock()
+		requests++
+		mu.Unlock()
+		var results []BulkResult
+		for _, doc := range req.Docs {
+			if doc.Name == "conflict" {
+				results = append(results, BulkResult{ID: doc.ID, Error: "conflict"})
+			} else {
+				results = append(results, BulkResult{ID: doc.ID, Rev: "1-" + doc.ID})
+			}
+		}
+		w.WriteHeader(201)
+		json.NewEncoder(w).Encode(results)
+	})
+	defer srv.Close()
+	c := newTestClient(srv)
+	docs := make([]testPerson, 10)
+	for i := range docs {
+		docs[i].ID = strconv.Itoa(i)
+	}
+	docs[7].Name = "conflict"
+	var last BatchProgress
+	calls := 0
+	results, err := BulkDocsBatched(c, "test_db", docs, BatchOptions{BatchSize: 3, Concurrency: 2, OnProgress: func(p BatchProgress) {
+		calls++
+		last = p
+	}})
+	if err != nil || len(results) != 10 || requests != 4 || calls != 4 {
+		t.Fatal("Unexpected results ", results, err, requests, calls)
+	}
+	if last.Completed != 4 || last.Written != 9 || last.Failed != 1 {
+		t.Error("Unexpected progress ", last)
+	}
+	if docs[9].Rev != "1-9" || results[9].ID != "9" || results[7].Index != 7 || !results[7].IsConflict() {
+		t.Error("Expected results in the same order of the documents, got ", results)
+	}
+	if _, err = BulkDocsBatched(c, "test_db", []string{`{}`, string(make([]byte, 1048576))}, BatchOptions{}); !errors.Is(err, ErrDocumentTooLarge) {
+		t.Error("Expected error for document bigger than 1MB, got ", err)
+	}
+	// Every batch greater than 3 documents is rejected by the fake server
+	if _, err = BulkDocsBatched(c, "test_db", docs, BatchOptions{BatchSize: 5}); !IsBadRequest(err) {
+		t.Error("Expected bad request, got ", err)
+	}
+}
+
+func TestBulkDocsBatchedFailedBatch(t *testing.T) {
+	srv := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Docs []testPerson `json:"docs"`
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		json.Unmarshal(body, &req)
+		var results []BulkResult
+		for _, doc := range req.Docs {
+			if doc.Name == "invalid" {
+				w.WriteHeader(400)
+				return
+			}
+			results = append(results, BulkResult{ID: doc.ID, Rev: "1-" + doc.ID})
+		}
+		w.WriteHeader(201)
+		json.NewEncoder(w).Encode(results)
+	})
+	defer srv.Close()
+	c := newTestClient(srv)
+	docs := make([]testPerson, 9)
+	for i := range docs {
+		docs[i].ID = strconv.Itoa(i)
+	}
+	docs[1].Name = "invalid"
+	// The first batch fails, the other ones are aborted
+	results, err := BulkDocsBatched(c, "test_db", docs, BatchOptions{BatchSize: 3, Concurrency: 1})
+	if !IsBadRequest(err) || len(results.Failures()) != 9 {
+		t.Fatal("Expected every document reported as failed, got ", results, err)
+	}
+	for i, r := range results {
+		if r.Index != i || r.ID != docs[i].ID || r.Error != NotSent || r.Reason == "" {
+			t.Error("Unexpected result for document ", i, ": ", r)
+		}
+	}
+	// The documents not sent can be retried
+	docs[1].Name = ""
+	if results, err = RetryBulkDocs(c, "test_db", docs, results, nil); err != nil || len(results.Failures()) != 0 || docs[8].Rev != "1-8" {
+		t.Error("Expected every document written by the retry, got ", results, err)
+	}
+}
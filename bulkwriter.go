@@ -0,0 +1,265 @@
+package cloudant
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// ErrWriterClosed is returned when a document is written into a BulkWriter alredy closed
+var ErrWriterClosed = errors.New("cloudant: bulk writer closed")
+
+// BulkWriterOptions is delegated to customize when the BulkWriter send the documents
+type BulkWriterOptions struct {
+	// Maximum number of documents for every request, 500 if not set
+	BatchSize int
+	// Maximum size in bytes of every request, 10MB (the Cloudant limit) if not set or greater
+	MaxBatchBytes int
+	// Maximum time that a document wait before being sent, 1 second if not set
+	FlushInterval time.Duration
+	// Number of requests sent at the same time, 4 if not set. Write blocks when all of them are in progress
+	Concurrency int
+	// Called with the outcome of every document, never concurrently. It can call Write for send again the failed
+	// documents, but it must not call Flush or Close: they wait for the request that is reporting the outcome
+	OnResult func(WriteResult)
+}
+
+// WriteResult is delegated to save the outcome of a document written by the BulkWriter
+type WriteResult struct {
+	// Document as sent to Cloudant
+	Doc json.RawMessage
+	// Outcome of the document, Index is the position of the document in the batch
+	BulkResult
+	// Error of the whole request, the document was not sent when it is not nil
+	Err error
+}
+
+// Failed return true if the document was not written, due to the request or to Cloudant
+func (r WriteResult) Failed() bool {
+	return r.Err != nil || r.BulkResult.Failed()
+}
+
+// BulkWriter is delegated to collect the documents produced by many goroutines and to send them with `_bulk_docs`.
+// A batch is sent as soon as it reaches the size set in the options, or when the oldest document waited for the
+// FlushInterval. The outcome of every document is reported asynchronously with OnResult.
+// Close have to be called for send the remaining documents before the shutdown of the application.
+// It is safe for concurrent use
+type BulkWriter struct {
+	c      *Client
+	dbName string
+	opts   BulkWriterOptions
+	ctx    context.Context
+
+	// mu guard the fields related to the batch in progress and the number of batches not completed
+	mu      sync.Mutex
+	pending []json.RawMessage
+	size    int
+	timer   *time.Timer
+	closed  bool
+	// inflight count the batches detached and not yet reported, idle is signaled when one of them completes
+	inflight int
+	idle     *sync.Cond
+
+	// sem limit the number of requests in progress
+	sem chan struct{}
+
+	// resultMu serialize OnResult and guard err
+	resultMu sync.Mutex
+	err      error
+}
+
+// NewBulkWriter is delegated to initialize a new BulkWriter for the given DB
+func NewBulkWriter(c *Client, dbName string, opts BulkWriterOptions) *BulkWriter {
+	return NewBulkWriterContext(context.Background(), c, dbName, opts)
+}
+
+// NewBulkWriterContext is the same as NewBulkWriter, but every request is bound to the given context
+func NewBulkWriterContext(ctx context.Context, c *Client, dbName string, opts BulkWriterOptions) *BulkWriter {
+	batch := BatchOptions{BatchSize: opts.BatchSize, MaxBatchBytes: opts.MaxBatchBytes, Concurrency: opts.Concurrency}.withDefaults()
+	opts.BatchSize, opts.MaxBatchBytes, opts.Concurrency = batch.BatchSize, batch.MaxBatchBytes, batch.Concurrency
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = time.Second
+	}
+	w := &BulkWriter{c: c, dbName: dbName, opts: opts, ctx: ctx, sem: make(chan struct{}, opts.Concurrency)}
+	w.idle = sync.NewCond(&w.mu)
+	return w
+}
+
+// Write is delegated to add a document to the batch in progress.
+// []byte, json.RawMessage and string are considered alredy encoded, any other value is encoded as JSON.
+// A document greater than 1MB is rejected with ErrDocumentTooLarge
+func (w *BulkWriter) Write(doc interface{}) error {
+	data, err := marshalDocument(doc)
+	if err != nil {
+		return err
+	}
+	// The batches are sent after releasing the lock, the other writers are not blocked while waiting for a request
+	var batches [][]json.RawMessage
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return ErrWriterClosed
+	}
+	// {"docs":[]} plus the comma between the documents
+	if len(w.pending) > 0 && w.size+len(data)+1 > w.opts.MaxBatchBytes {
+		batches = append(batches, w.detachLocked())
+	}
+	if len(w.pending) == 0 {
+		w.size = len(`{"docs":[]}`)
+		w.timer = time.AfterFunc(w.opts.FlushInterval, w.flushPending)
+	}
+	w.pending = append(w.pending, data)
+	w.size += len(data) + 1
+	if len(w.pending) >= w.opts.BatchSize {
+		batches = append(batches, w.detachLocked())
+	}
+	w.mu.Unlock()
+	for _, batch := range batches {
+		w.send(batch)
+	}
+	return nil
+}
+
+// WriteFromReader is delegated to write every JSON document read from the given stream, until EOF.
+// The documents can be separated by spaces or new lines (ex: NDJSON)
+// Return the number of documents written
+func (w *BulkWriter) WriteFromReader(r io.Reader) (int, error) {
+	decoder := json.NewDecoder(r)
+	n := 0
+	for {
+		var doc json.RawMessage
+		if err := decoder.Decode(&doc); err == io.EOF {
+			return n, nil
+		} else if err != nil {
+			return n, fmt.Errorf("cloudant: unable to decode document %d: %w", n, err)
+		}
+		if err := w.Write(doc); err != nil {
+			return n, err
+		}
+		n++
+	}
+}
+
+// WriteFromChannel is delegated to write every document received from the given channel, until it is closed
+// Return the number of documents written
+func WriteFromChannel[T any](w *BulkWriter, docs <-chan T) (int, error) {
+	n := 0
+	for doc := range docs {
+		if err := w.Write(doc); err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, nil
+}
+
+// Flush is delegated to send the batch in progress and to wait for the completion of every request, including the
+// ones of the documents written meanwhile by OnResult.
+// Return the first request error occurred since the previous Flush
+func (w *BulkWriter) Flush() error {
+	w.drain()
+	w.resultMu.Lock()
+	defer w.resultMu.Unlock()
+	err := w.err
+	w.err = nil
+	return err
+}
+
+// Close is delegated to send the remaining documents and to wait for the completion of every request.
+// The documents written after Close are rejected with ErrWriterClosed
+func (w *BulkWriter) Close() error {
+	// Let OnResult send again the failed documents before rejecting new ones
+	w.drain()
+	w.mu.Lock()
+	w.closed = true
+	w.mu.Unlock()
+	return w.Flush()
+}
+
+// drain is delegated to send the pending documents until every batch is completed
+func (w *BulkWriter) drain() {
+	w.mu.Lock()
+	for len(w.pending) > 0 || w.inflight > 0 {
+		if len(w.pending) > 0 {
+			batch := w.detachLocked()
+			w.mu.Unlock()
+			w.send(batch)
+			w.mu.Lock()
+			continue
+		}
+		w.idle.Wait()
+	}
+	w.mu.Unlock()
+}
+
+// flushPending is delegated to send the batch in progress, it is used by the timer
+func (w *BulkWriter) flushPending() {
+	w.mu.Lock()
+	batch := w.detachLocked()
+	w.mu.Unlock()
+	w.send(batch)
+}
+
+// detachLocked is delegated to remove the batch in progress, counting it as in flight until send complete it.
+// w.mu have to be held
+func (w *BulkWriter) detachLocked() []json.RawMessage {
+	if w.timer != nil {
+		w.timer.Stop()
+		w.timer = nil
+	}
+	if len(w.pending) == 0 {
+		return nil
+	}
+	batch := w.pending
+	w.pending, w.size = nil, 0
+	w.inflight++
+	return batch
+}
+
+// send is delegated to send a batch returned by detachLocked in background, waiting if too many requests are running.
+// w.mu must not be held
+func (w *BulkWriter) send(batch []json.RawMessage) {
+	if len(batch) == 0 {
+		return
+	}
+	w.sem <- struct{}{}
+	go func() {
+		zap.S().Debug("BulkWriter | Sending ", len(batch), " documents into [", w.dbName, "] ...")
+		results, err := BulkDocsContext(w.ctx, w.c, w.dbName, batch)
+		// The slot is released before OnResult, that can send the documents again
+		<-w.sem
+		if err != nil {
+			zap.S().Error("BulkWriter | ERROR! Unable to send ", len(batch), " documents | Err: ", err)
+		}
+		w.report(batch, results, err)
+		w.mu.Lock()
+		w.inflight--
+		w.idle.Broadcast()
+		w.mu.Unlock()
+	}()
+}
+
+// report is delegated to save the request error and to call OnResult for every document of the batch
+func (w *BulkWriter) report(batch []json.RawMessage, results BulkResults, err error) {
+	w.resultMu.Lock()
+	defer w.resultMu.Unlock()
+	if err != nil && w.err == nil {
+		w.err = err
+	}
+	if w.opts.OnResult == nil {
+		return
+	}
+	for i := range batch {
+		result := WriteResult{Doc: batch[i], Err: err}
+		if err == nil {
+			result.BulkResult = results[i]
+		}
+		w.opts.OnResult(result)
+	}
+}
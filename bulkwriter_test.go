@@ -0,0 +1,209 @@
+package cloudant
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// newTestWriterServer is delegated to initialize a fake instance that accept every document with an `_id`.
+// The number of documents of every request is sent into the given channel, if not nil
+func newTestWriterServer(sizes chan<- int) *httptest.Server {
+	return newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Docs []Document `json:"docs"`
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		if json.Unmarshal(body, &req) != nil {
+			w.WriteHeader(400)
+			return
+		}
+		if sizes != nil {
+			sizes <- len(req.Docs)
+		}
+		results := make([]BulkResult, len(req.Docs))
+		for i, doc := range req.Docs {
+			results[i] = BulkResult{ID: doc.ID, Rev: "1-x"}
+			if doc.ID == "" {
+				results[i].Error = "bad_request"
+			}
+		}
+		w.WriteHeader(201)
+		json.NewEncoder(w).Encode(results)
+	})
+}
+
+func TestBulkWriter(t *testing.T) {
+	sizes := make(chan int, 100)
+	srv := newTestWriterServer(sizes)
+	defer srv.Close()
+	var mu sync.Mutex
+	written, failed := 0, 0
+	w := NewBulkWriter(newTestClient(srv), "test_db", BulkWriterOptions{BatchSize: 10, FlushInterval: time.Hour, OnResult: func(r WriteResult) {
+		mu.Lock()
+		defer mu.Unlock()
+		if r.Failed() {
+			failed++
+		} else {
+			written++
+		}
+	}})
+	var wg sync.WaitGroup
+	for g := 0; g < 5; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 21; i++ {
+				if err := w.Write(Document{ID: strconv.Itoa(g*100 + i)}); err != nil {
+					t.Error(err)
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+	if err := w.Write(map[string]string{"name": "without id"}); err != nil {
+		t.Error(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Error(err)
+	}
+	close(sizes)
+	batches, total := 0, 0
+	for size := range sizes {
+		if size > 10 {
+			t.Error("Expected at most 10 documents for batch, got ", size)
+		}
+		batches++
+		total += size
+	}
+	if batches != 11 || total != 106 || written != 105 || failed != 1 {
+		t.Error("Unexpected outcome ", batches, total, written, failed)
+	}
+	if err := w.Write(Document{ID: "closed"}); !errors.Is(err, ErrWriterClosed) {
+		t.Error("Expected writer closed, got ", err)
+	}
+}
+
+func TestBulkWriterFlushInterval(t *testing.T) {
+	srv := newTestWriterServer(nil)
+	defer srv.Close()
+	results := make(chan WriteResult, 1)
+	w := NewBulkWriter(newTestClient(srv), "test_db", BulkWriterOptions{FlushInterval: 10 * time.Millisecond, OnResult: func(r WriteResult) {
+		results <- r
+	}})
+	defer w.Close()
+	if err := w.Write(`{"_id":"1"}`); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case r := <-results:
+		if r.Failed() || r.ID != "1" || string(r.Doc) != `{"_id":"1"}` {
+			t.Error("Unexpected result ", r)
+		}
+	case <-time.After(5 * time.Second):
+		t.Error("Expected document sent after the flush interval")
+	}
+}
+
+func TestBulkWriterSources(t *testing.T) {
+	sizes := make(chan int, 10)
+	srv := newTestWriterServer(sizes)
+	defer srv.Close()
+	w := NewBulkWriter(newTestClient(srv), "test_db", BulkWriterOptions{FlushInterval: time.Hour})
+	if n, err := w.WriteFromReader(strings.NewReader("{\"_id\":\"1\"}\n{\"_id\":\"2\"}\n")); n != 2 || err != nil {
+		t.Error("Expected 2 documents from reader, got ", n, err)
+	}
+	docs := make(chan Document, 3)
+	for i := 0; i < 3; i++ {
+		docs <- Document{ID: strconv.Itoa(i)}
+	}
+	close(docs)
+	if n, err := WriteFromChannel(w, docs); n != 3 || err != nil {
+		t.Error("Expected 3 documents from channel, got ", n, err)
+	}
+	if err := w.Flush(); err != nil || <-sizes != 5 {
+		t.Error("Expected a single batch of 5 documents, got ", err)
+	}
+	if _, err := w.WriteFromReader(strings.NewReader(`{"_id":`)); err == nil {
+		t.Error("Expected error for malformed document")
+	}
+}
+
+func TestBulkWriterRequestError(t *testing.T) {
+	srv := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(403)
+	})
+	defer srv.Close()
+	failed := 0
+	w := NewBulkWriter(newTestClient(srv), "test_db", BulkWriterOptions{OnResult: func(r WriteResult) {
+		if r.Err != nil {
+			failed++
+		}
+	}})
+	w.Write(Document{ID: "1"})
+	w.Write(Document{ID: "2"})
+	if err := w.Close(); !IsForbidden(err) || failed != 2 {
+		t.Error("Expected forbidden for every document, got ", err, failed)
+	}
+}
+
+func TestBulkWriterRetryFromOnResult(t *testing.T) {
+	var mu sync.Mutex
+	attempts := map[string]int{}
+	srv := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Docs []Document `json:"docs"`
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		json.Unmarshal(body, &req)
+		results := make([]BulkResult, len(req.Docs))
+		mu.Lock()
+		for i, doc := range req.Docs {
+			// Every document is rejected the first time
+			if attempts[doc.ID]++; attempts[doc.ID] == 1 {
+				results[i] = BulkResult{ID: doc.ID, Error: "conflict"}
+			} else {
+				results[i] = BulkResult{ID: doc.ID, Rev: "1-x"}
+			}
+		}
+		mu.Unlock()
+		w.WriteHeader(201)
+		json.NewEncoder(w).Encode(results)
+	})
+	defer srv.Close()
+	written := 0
+	var w *BulkWriter
+	w = NewBulkWriter(newTestClient(srv), "test_db", BulkWriterOptions{BatchSize: 1, Concurrency: 1, FlushInterval: time.Hour, OnResult: func(r WriteResult) {
+		if r.Failed() {
+			if err := w.Write(r.Doc); err != nil {
+				t.Error(err)
+			}
+			return
+		}
+		written++
+	}})
+	done := make(chan error)
+	go func() {
+		for i := 0; i < 5; i++ {
+			if err := w.Write(Document{ID: strconv.Itoa(i)}); err != nil {
+				t.Error(err)
+			}
+		}
+		done <- w.Close()
+	}()
+	select {
+	case err := <-done:
+		if err != nil || written != 5 {
+			t.Error("Expected every document written by the retry, got ", written, err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Deadlock writing from OnResult")
+	}
+}
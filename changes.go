@@ -0,0 +1,364 @@
+package cloudant
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Types of changes feed supported by Cloudant
+const (
+	// FeedNormal return the changes available and close the response
+	FeedNormal = "normal"
+	// FeedLongPoll wait until at least one change is available; the iterator request the next changes automatically
+	FeedLongPoll = "longpoll"
+	// FeedContinuous keep the response open and send every change as soon as it happens
+	FeedContinuous = "continuous"
+)
+
+// StyleAllDocs return every leaf revision of the documents, including the conflicts
+const StyleAllDocs = "all_docs"
+
+// Seq is delegated to save a sequence of the changes feed. It is an opaque string in Cloudant, a number in the
+// old CouchDB versions
+type Seq string
+
+// UnmarshalJSON is delegated to decode the sequence from a string or a number.
+// null (sent when SeqInterval is set) is decoded as an empty sequence
+func (s *Seq) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		*s = ""
+		return nil
+	}
+	if len(data) > 0 && data[0] == '"' {
+		var value string
+		if err := json.Unmarshal(data, &value); err != nil {
+			return err
+		}
+		*s = Seq(value)
+		return nil
+	}
+	*s = Seq(data)
+	return nil
+}
+
+// ChangesOptions is delegated to customize the changes returned by Changes
+// https://cloud.ibm.com/docs/services/Cloudant?topic=cloudant-databases#get-changes
+type ChangesOptions struct {
+	// Type of feed (FeedNormal, FeedLongPoll, FeedContinuous), FeedNormal if not set
+	Feed string
+	// Return only the changes after the given sequence, "now" for the new changes only
+	Since Seq
+	// Return the content of the documents
+	IncludeDocs bool
+	// Return the conflicted revisions of the documents, only with IncludeDocs
+	Conflicts bool
+	// Revisions returned for every document, StyleAllDocs for every leaf revision
+	Style string
+	// Filter function used for select the changes (ex: ddoc/filter, _design, _view)
+	Filter string
+	// View used for select the changes, only with the `_view` Filter
+	View string
+	// Additional parameters passed to the filter function
+	Params map[string]string
+	// Return only the changes of the given documents
+	DocIDs []string
+	// Return only the changes of the documents that match the selector
+	Selector Selector
+	// Interval between the empty lines sent for keep the connection alive, with FeedLongPoll and FeedContinuous
+	Heartbeat time.Duration
+	// Time after which the response is closed when there are no changes, with FeedLongPoll and FeedContinuous
+	Timeout time.Duration
+	// Maximum number of changes for every request
+	Limit int
+	// Return the changes from the newest to the oldest, only with FeedNormal
+	Descending bool
+	// Compute the sequence only every N changes, for reduce the load of the server.
+	// The Seq of the other changes is empty
+	SeqInterval int
+}
+
+// request is delegated to compose the query parameters and the body (for DocIDs and Selector) of the request
+func (opts ChangesOptions) request(since Seq) (url.Values, []byte, error) {
+	q := url.Values{}
+	for name, value := range opts.Params {
+		q.Set(name, value)
+	}
+	set := func(name, value string, ok bool) {
+		if ok {
+			q.Set(name, value)
+		}
+	}
+	set("feed", opts.Feed, opts.Feed != "")
+	set("since", string(since), since != "")
+	set("include_docs", "true", opts.IncludeDocs)
+	set("conflicts", "true", opts.Conflicts)
+	set("descending", "true", opts.Descending)
+	set("style", opts.Style, opts.Style != "")
+	set("filter", opts.Filter, opts.Filter != "")
+	set("view", opts.View, opts.View != "")
+	set("heartbeat", strconv.FormatInt(opts.Heartbeat.Milliseconds(), 10), opts.Heartbeat > 0)
+	set("timeout", strconv.FormatInt(opts.Timeout.Milliseconds(), 10), opts.Timeout > 0)
+	set("limit", strconv.Itoa(opts.Limit), opts.Limit > 0)
+	set("seq_interval", strconv.Itoa(opts.SeqInterval), opts.SeqInterval > 0)
+	var body interface{}
+	switch {
+	case opts.DocIDs != nil && opts.Selector != nil:
+		return nil, nil, fmt.Errorf("%w: DocIDs and Selector can not be used together", ErrInvalidArgument)
+	case opts.DocIDs != nil:
+		q.Set("filter", "_doc_ids")
+		body = map[string][]string{"doc_ids": opts.DocIDs}
+	case opts.Selector != nil:
+		q.Set("filter", "_selector")
+		body = map[string]Selector{"selector": opts.Selector}
+	default:
+		return q, nil, nil
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: unable to encode filter: %v", ErrInvalidArgument, err)
+	}
+	return q, data, nil
+}
+
+// ChangeEvent is delegated to save a change of a document
+type ChangeEvent struct {
+	// Sequence of the change, can be used as Since for resume the feed. Empty when not computed (see SeqInterval)
+	Seq Seq `json:"seq"`
+	// `_id` of the document
+	ID string `json:"id"`
+	// Revisions of the document related to the change
+	Changes []struct {
+		Rev string `json:"rev"`
+	} `json:"changes"`
+	// True if the document was deleted
+	Deleted bool `json:"deleted,omitempty"`
+	// Content of the document, only with IncludeDocs
+	Doc json.RawMessage `json:"doc,omitempty"`
+}
+
+// DecodeDoc is delegated to decode the content of the document into v, only with IncludeDocs
+func (e ChangeEvent) DecodeDoc(v interface{}) error {
+	if len(e.Doc) == 0 {
+		return fmt.Errorf("cloudant: document %s not included in the change", e.ID)
+	}
+	return json.Unmarshal(e.Doc, v)
+}
+
+// ChangesIterator is delegated to iterate over the changes of a DB, decoding them while they are received.
+// With FeedLongPoll and FeedContinuous the iteration never ends: the feed is requested again from the last sequence
+// every time that the server close the response, until the context is cancelled or Close is called.
+// It is not safe for concurrent use: cancel the context for stop a Next blocked waiting for changes
+//
+//	ctx, cancel := context.WithCancel(context.Background())
+//	defer cancel()
+//	it := client.ChangesContext(ctx, "db", cloudant.ChangesOptions{Feed: cloudant.FeedContinuous, Since: "now"})
+//	defer it.Close()
+//	for it.Next() {
+//		fmt.Println(it.Change().ID)
+//	}
+//	if err := it.Err(); err != nil && !errors.Is(err, context.Canceled) {
+//		...
+//	}
+//
+// NOTE: The Timeout of the HTTP client of the Client have to be greater than the Heartbeat, or disabled
+type ChangesIterator struct {
+	c      *Client
+	ctx    context.Context
+	cancel context.CancelFunc
+	dbName string
+	opts   ChangesOptions
+
+	// Sequence of the last change received, used for the next request
+	since Seq
+	// Response of FeedNormal and FeedLongPoll
+	page *rowDecoder
+	// Response of FeedContinuous
+	body io.ReadCloser
+	dec  *json.Decoder
+
+	change ChangeEvent
+	err    error
+	done   bool
+}
+
+// Changes is delegated to return an iterator over the changes of the given DB.
+// The request is sent during the first call to Next
+// dbName: DB that we want to follow
+// opts: type of feed, starting sequence and filters of the changes
+func (c *Client) Changes(dbName string, opts ChangesOptions) *ChangesIterator {
+	return c.ChangesContext(context.Background(), dbName, opts)
+}
+
+// ChangesContext is the same as Changes, but the requests are bound to the given context.
+// The iteration stops, and the connection is closed, as soon as the context is cancelled
+func (c *Client) ChangesContext(ctx context.Context, dbName string, opts ChangesOptions) *ChangesIterator {
+	ctx, cancel := context.WithCancel(ctx)
+	it := &ChangesIterator{c: c, ctx: ctx, cancel: cancel, dbName: dbName, opts: opts, since: opts.Since}
+	if dbName == "" {
+		it.err = fmt.Errorf("%w: DB name not provided", ErrInvalidArgument)
+	}
+	if opts.Feed != "" && opts.Feed != FeedNormal && opts.Feed != FeedLongPoll && opts.Feed != FeedContinuous {
+		it.err = fmt.Errorf("%w: feed [%s] not supported", ErrInvalidArgument, opts.Feed)
+	}
+	return it
+}
+
+// Next is delegated to advance to the next change, requesting the feed again when the server close the response.
+// Return false when there are no more changes, the context is cancelled or an error occurs (see Err)
+func (it *ChangesIterator) Next() bool {
+	for {
+		if it.err != nil || it.done {
+			return false
+		}
+		if it.err = it.ctx.Err(); it.err != nil {
+			it.Close()
+			return false
+		}
+		if it.page == nil && it.body == nil {
+			if it.err = it.open(); it.err != nil {
+				it.failed()
+				return false
+			}
+		}
+		var ok bool
+		if it.opts.Feed == FeedContinuous {
+			ok, it.err = it.nextContinuous()
+		} else {
+			ok, it.err = it.nextPage()
+		}
+		if it.err != nil {
+			it.failed()
+			return false
+		}
+		if ok {
+			return true
+		}
+	}
+}
+
+// failed is delegated to close the iterator after an error, reporting the cancellation of the context when it is the cause
+func (it *ChangesIterator) failed() {
+	if ctxErr := it.ctx.Err(); ctxErr != nil && !errors.Is(it.err, ctxErr) {
+		it.err = ctxErr
+	}
+	it.Close()
+}
+
+// nextPage is delegated to read the next change of a FeedNormal or FeedLongPoll response.
+// When the response is consumed, the last sequence is used for the next request
+func (it *ChangesIterator) nextPage() (bool, error) {
+	var change ChangeEvent
+	ok, err := it.page.next(&change)
+	if err != nil || ok {
+		it.change = change
+		if change.Seq != "" {
+			it.since = change.Seq
+		}
+		return ok, err
+	}
+	var last Seq
+	if err = it.page.decodeMeta("last_seq", &last); err != nil {
+		return false, fmt.Errorf("cloudant: unable to decode last_seq: %w", err)
+	}
+	it.page = nil
+	if last != "" {
+		it.since = last
+	}
+	it.done = it.opts.Feed != FeedLongPoll
+	return false, nil
+}
+
+// nextContinuous is delegated to read the next line of a FeedContinuous response.
+// When the server close the response, the feed is requested again from the last sequence
+func (it *ChangesIterator) nextContinuous() (bool, error) {
+	var line struct {
+		ChangeEvent
+		LastSeq *Seq `json:"last_seq"`
+	}
+	err := it.dec.Decode(&line)
+	if err == io.EOF || err == nil && line.LastSeq != nil {
+		if line.LastSeq != nil && *line.LastSeq != "" {
+			it.since = *line.LastSeq
+		}
+		zap.S().Debug("Changes | Feed closed by the server, reconnecting from [", it.since, "]")
+		it.body.Close()
+		it.body, it.dec = nil, nil
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("cloudant: unable to decode change: %w", err)
+	}
+	it.change = line.ChangeEvent
+	if line.Seq != "" {
+		it.since = line.Seq
+	}
+	return true, nil
+}
+
+// open is delegated to request the feed from the current sequence
+func (it *ChangesIterator) open() error {
+	q, body, err := it.opts.request(it.since)
+	if err != nil {
+		return err
+	}
+	method := `GET`
+	if body != nil {
+		method = `POST`
+	}
+	URL := it.c.databaseURL(it.dbName) + `/_changes?` + q.Encode()
+	zap.S().Debug("Changes | Sending request to URL: [", URL, "]")
+	headers := newHeader(`Accept`, `application/json`, `Content-Type`, `application/json`)
+	res, err := it.c.stream(it.ctx, method, URL, headers, body)
+	if err != nil {
+		return err
+	}
+	if it.opts.Feed == FeedContinuous {
+		it.body, it.dec = res.Body, json.NewDecoder(res.Body)
+		return nil
+	}
+	it.page, err = newRowDecoder(res.Body, "results")
+	return err
+}
+
+// Change is delegated to return the current change
+func (it *ChangesIterator) Change() ChangeEvent {
+	return it.change
+}
+
+// LastSeq is delegated to return the sequence of the last change received (or of the last response consumed),
+// that can be saved and used as Since for resume the feed later
+func (it *ChangesIterator) LastSeq() Seq {
+	return it.since
+}
+
+// Err is delegated to return the error occurred during the iteration, if any.
+// It is the error of the context when the iteration is stopped by the cancellation
+func (it *ChangesIterator) Err() error {
+	return it.err
+}
+
+// Close is delegated to stop the iteration, releasing the connection in use.
+// It is safe to call it multiple times
+func (it *ChangesIterator) Close() error {
+	it.done = true
+	it.cancel()
+	if it.page != nil {
+		it.page.close()
+		it.page = nil
+	}
+	if it.body != nil {
+		it.body.Close()
+		it.body, it.dec = nil, nil
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+package cloudant
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestChangesOptions(t *testing.T) {
+	q, body, err := ChangesOptions{Feed: FeedLongPoll, IncludeDocs: true, Style: StyleAllDocs, Heartbeat: 5 * time.Second,
+		Timeout: time.Minute, DocIDs: []string{"1", "2"}, Params: map[string]string{"type": "user"}}.request("10-abc")
+	if err != nil || q.Encode() != "feed=longpoll&filter=_doc_ids&heartbeat=5000&include_docs=true&since=10-abc&style=all_docs&timeout=60000&type=user" || string(body) != `{"doc_ids":["1","2"]}` {
+		t.Error("Unexpected request ", q.Encode(), string(body), err)
+	}
+	q, body, err = ChangesOptions{Selector: Field("type").Eq("user")}.request("")
+	if err != nil || q.Encode() != "filter=_selector" || string(body) != `{"selector":{"type":{"$eq":"user"}}}` {
+		t.Error("Unexpected request ", q.Encode(), string(body), err)
+	}
+	if _, _, err = (ChangesOptions{Selector: Selector{}, DocIDs: []string{}}).request(""); !errors.Is(err, ErrInvalidArgument) {
+		t.Error("Expected invalid argument, got ", err)
+	}
+}
+
+func TestChangesNormal(t *testing.T) {
+	srv := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		if r.URL.Path != "/test_db/_changes" || r.Method != "POST" || string(body) != `{"doc_ids":["1","2"]}` {
+			w.WriteHeader(400)
+			return
+		}
+		w.Write([]byte(`{"results":[
+			{"seq":"1-a","id":"1","changes":[{"rev":"1-x"}],"doc":{"_id":"1","name":"mario"}},
+			{"seq":"2-b","id":"2","changes":[{"rev":"2-x"}],"deleted":true}],"last_seq":"2-b","pending":0}`))
+	})
+	defer srv.Close()
+	it := newTestClient(srv).Changes("test_db", ChangesOptions{IncludeDocs: true, DocIDs: []string{"1", "2"}})
+	defer it.Close()
+	var person testPerson
+	if !it.Next() || it.Change().ID != "1" || it.Change().DecodeDoc(&person) != nil || person.Name != "mario" || it.LastSeq() != "1-a" {
+		t.Fatal("Unexpected change ", it.Change(), it.Err())
+	}
+	if !it.Next() || !it.Change().Deleted || it.Change().Changes[0].Rev != "2-x" {
+		t.Fatal("Unexpected change ", it.Change(), it.Err())
+	}
+	if it.Next() || it.Err() != nil || it.LastSeq() != "2-b" {
+		t.Error("Expected end of the feed, got ", it.Err(), it.LastSeq())
+	}
+}
+
+func TestChangesLongPoll(t *testing.T) {
+	srv := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("since") {
+		case "0":
+			w.Write([]byte(`{"results":[{"seq":1,"id":"1","changes":[]},{"seq":2,"id":"2","changes":[]}],"last_seq":2}`))
+		case "2":
+			w.Write([]byte(`{"results":[{"seq":3,"id":"3","changes":[]}],"last_seq":3}`))
+		default:
+			// No more changes, wait until the client goes away
+			<-r.Context().Done()
+		}
+	})
+	defer srv.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	it := newTestClient(srv).ChangesContext(ctx, "test_db", ChangesOptions{Feed: FeedLongPoll, Since: "0"})
+	defer it.Close()
+	var ids string
+	for i := 0; i < 3 && it.Next(); i++ {
+		ids += it.Change().ID
+	}
+	if ids != "123" || it.Change().Seq != "3" {
+		t.Fatal("Unexpected changes ", ids, it.Err())
+	}
+	time.AfterFunc(50*time.Millisecond, cancel)
+	if it.Next() || !errors.Is(it.Err(), context.Canceled) || it.LastSeq() != "3" {
+		t.Error("Expected feed stopped by the context, got ", it.Err(), it.LastSeq())
+	}
+}
+
+func TestChangesContinuous(t *testing.T) {
+	requests := make(chan string, 10)
+	srv := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		since := r.URL.Query().Get("since")
+		requests <- since
+		if r.URL.Query().Get("feed") != FeedContinuous {
+			w.WriteHeader(400)
+			return
+		}
+		flusher := w.(http.Flusher)
+		switch since {
+		case "now":
+			for i := 1; i <= 2; i++ {
+				json.NewEncoder(w).Encode(map[string]interface{}{"seq": fmt.Sprintf("%d-x", i), "id": fmt.Sprint(i), "changes": []interface{}{}})
+				w.Write([]byte("\n"))
+				flusher.Flush()
+			}
+			// The server close the feed after the timeout
+			w.Write([]byte(`{"last_seq":"2-x","pending":0}` + "\n"))
+		case "2-x":
+			w.Write([]byte(`{"seq":"3-x","id":"3","changes":[]}` + "\n"))
+			flusher.Flush()
+			<-r.Context().Done()
+		}
+	})
+	defer srv.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	it := newTestClient(srv).ChangesContext(ctx, "test_db", ChangesOptions{Feed: FeedContinuous, Since: "now", Heartbeat: time.Second})
+	defer it.Close()
+	var ids string
+	for i := 0; i < 3 && it.Next(); i++ {
+		ids += it.Change().ID
+	}
+	if ids != "123" || it.LastSeq() != "3-x" || <-requests != "now" || <-requests != "2-x" {
+		t.Fatal("Unexpected changes ", ids, it.Err())
+	}
+	time.AfterFunc(50*time.Millisecond, cancel)
+	if it.Next() || !errors.Is(it.Err(), context.Canceled) {
+		t.Error("Expected feed stopped by the context, got ", it.Err())
+	}
+	if it = newTestClient(srv).Changes("test_db", ChangesOptions{Feed: "unknown"}); it.Next() || !errors.Is(it.Err(), ErrInvalidArgument) {
+		t.Error("Expected invalid argument, got ", it.Err())
+	}
+}
+
+func TestChangesSeqInterval(t *testing.T) {
+	requests := make(chan string, 10)
+	srv := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		since := r.URL.Query().Get("since")
+		requests <- since
+		if r.URL.Query().Get("seq_interval") != "2" {
+			w.WriteHeader(400)
+			return
+		}
+		if since != "0" {
+			<-r.Context().Done()
+			return
+		}
+		// The sequence is computed only for the second change, the server close the feed without last_seq
+		w.Write([]byte(`{"seq":null,"id":"1","changes":[]}` + "\n" + `{"seq":"2-x","id":"2","changes":[]}` + "\n" + `{"seq":null,"id":"3","changes":[]}` + "\n"))
+	})
+	defer srv.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	it := newTestClient(srv).ChangesContext(ctx, "test_db", ChangesOptions{Feed: FeedContinuous, Since: "0", SeqInterval: 2})
+	defer it.Close()
+	if !it.Next() || it.Change().Seq != "" || it.LastSeq() != "0" {
+		t.Fatal("Expected change without sequence, got ", it.Change(), it.LastSeq(), it.Err())
+	}
+	if !it.Next() || it.LastSeq() != "2-x" || !it.Next() || it.Change().ID != "3" || it.LastSeq() != "2-x" {
+		t.Fatal("Expected last computed sequence, got ", it.Change(), it.LastSeq(), it.Err())
+	}
+	time.AfterFunc(50*time.Millisecond, cancel)
+	if it.Next() || <-requests != "0" || <-requests != "2-x" || it.LastSeq() != "2-x" {
+		t.Error("Expected feed resumed from the last computed sequence, got ", it.LastSeq(), it.Err())
+	}
+}
@@ -0,0 +1,212 @@
+package cloudant
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"go.uber.org/zap"
+)
+
+// Client is delegated to execute every database and document operation against a Cloudant instance.
+// It owns the base URL, the credentials and the HTTP client used for send the requests, so it can be
+// initialized once and shared for the whole lifetime of the application
+type Client struct {
+	// Configuration used for initialize the client
+	conf Conf
+	// Authenticator used for decorate every request with the credentials
+	auth Authenticator
+	// URL related to the Cloudant instance
+	dbURL string
+	// HTTP client used for send the requests
+	httpClient *http.Client
+	// Policy used for retry the failed requests
+	retry RetryPolicy
+	// Limiter used for not exceed the throughput of the instance
+	limiter *RateLimiter
+}
+
+// Option is delegated to customize the Client during the initialization
+type Option func(*Client)
+
+// WithHTTPClient is delegated to set the HTTP client used for send every request.
+// When not provided, http.DefaultClient will be used
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		if httpClient != nil {
+			c.httpClient = httpClient
+		}
+	}
+}
+
+// WithAuthenticator is delegated to set the Authenticator used for decorate every request.
+// When not provided, the Authenticator is initialized from the configuration (see Conf.AuthType)
+func WithAuthenticator(auth Authenticator) Option {
+	return func(c *Client) {
+		if auth != nil {
+			c.auth = auth
+		}
+	}
+}
+
+// NewClient is delegated to initialize a new Client using the given configuration.
+// The credentials are retrieved lazily during the first request, using the HTTP client provided by the options
+func NewClient(conf Conf, opts ...Option) (*Client, error) {
+	dbURL, err := conf.BaseURL()
+	if err != nil {
+		zap.S().Error("NewClient | Unable to compute the URL of the instance | Err: ", err)
+		return nil, err
+	}
+	c := &Client{
+		conf:       conf,
+		dbURL:      dbURL,
+		httpClient: http.DefaultClient,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.auth == nil {
+		if c.auth, err = newAuthenticator(conf, c.dbURL, c.httpClient); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// URL is delegated to return the URL related to the Cloudant instance
+func (c *Client) URL() string {
+	return c.dbURL
+}
+
+// response is delegated to save the necessary information related to an HTTP call
+type response struct {
+	StatusCode int
+	Body       []byte
+	Header     http.Header
+}
+
+// newHeader is delegated to initialize the headers of a request.
+// The given strings are used as a list of key,value
+func newHeader(kv ...string) http.Header {
+	header := make(http.Header, len(kv)/2)
+	for i := 0; i+1 < len(kv); i += 2 {
+		header.Set(kv[i], kv[i+1])
+	}
+	return header
+}
+
+// send is delegated to execute an authenticated HTTP request using the client credentials.
+// The request is bound to the given context, so it will be aborted as soon as the context is cancelled.
+// Every attempt is delayed by the RateLimiter of the client, and the failed request are retried following the RetryPolicy.
+// A *CloudantError is returned when the server answer with an error status code
+func (c *Client) send(ctx context.Context, method, URL string, header http.Header, body []byte) (response, error) {
+	resp, _, err := c.do(ctx, readRequest, method, URL, header, body)
+	return resp, err
+}
+
+// stream is the same as send, but the body of a successful response is not read: the caller have to consume
+// and close it. It is used for decode large responses incrementally
+func (c *Client) stream(ctx context.Context, method, URL string, header http.Header, body []byte) (*http.Response, error) {
+	_, res, err := c.do(ctx, openRequest, method, URL, header, body)
+	return res, err
+}
+
+// requestFunc is the signature of readRequest and openRequest
+type requestFunc func(ctx context.Context, httpClient *http.Client, auth Authenticator, method, URL string, header http.Header, body []byte) (response, *http.Response, error)
+
+// do is delegated to execute the request with the given function, applying the RateLimiter and the RetryPolicy
+func (c *Client) do(ctx context.Context, fn requestFunc, method, URL string, header http.Header, body []byte) (response, *http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		if err := c.throttle(ctx, method, URL, body); err != nil {
+			return response{}, nil, err
+		}
+		resp, res, err := c.sendAuthenticated(ctx, fn, method, URL, header, body)
+		delay, retry := c.retry.retryDelay(attempt, method, URL, resp, err)
+		if !retry {
+			return resp, res, err
+		}
+		c.retry.notifyRetry(RetryEvent{Method: method, URL: URL, Attempt: attempt, StatusCode: resp.StatusCode, Err: err, Delay: delay})
+		if err := sleep(ctx, delay); err != nil {
+			return resp, nil, err
+		}
+	}
+}
+
+// sendAuthenticated is delegated to execute the HTTP request decorated by the client Authenticator.
+// If the credentials are rejected (401) and they can be renewed, the request is sent again once
+func (c *Client) sendAuthenticated(ctx context.Context, fn requestFunc, method, URL string, header http.Header, body []byte) (response, *http.Response, error) {
+	resp, res, err := fn(ctx, c.httpClient, c.auth, method, URL, header, body)
+	if inv, ok := c.auth.(Invalidator); ok && IsUnauthorized(err) {
+		zap.S().Warn("send | Credentials rejected, requesting new ones ...")
+		inv.Invalidate()
+		return fn(ctx, c.httpClient, c.auth, method, URL, header, body)
+	}
+	return resp, res, err
+}
+
+// sendRaw is delegated to execute the HTTP request as is, without adding any credentials.
+// A *CloudantError is returned when the server answer with an error status code
+func (c *Client) sendRaw(ctx context.Context, method, URL string, header http.Header, body []byte) (response, error) {
+	return sendRequest(ctx, c.httpClient, nil, method, URL, header, body)
+}
+
+// sendRequest is delegated to execute the HTTP request using the given HTTP client, reading the whole response.
+// The request is decorated by the given Authenticator, if not nil.
+// A *CloudantError is returned when the server answer with an error status code
+func sendRequest(ctx context.Context, httpClient *http.Client, auth Authenticator, method, URL string, header http.Header, body []byte) (response, error) {
+	resp, res, err := openRequest(ctx, httpClient, auth, method, URL, header, body)
+	if err != nil {
+		return resp, err
+	}
+	defer res.Body.Close()
+	if resp.Body, err = ioutil.ReadAll(res.Body); err != nil {
+		zap.S().Error("sendRequest | Unable to read response! | Err: ", err)
+		return resp, fmt.Errorf("cloudant: unable to read response of %s %s: %w", method, URL, err)
+	}
+	return resp, nil
+}
+
+// readRequest is the same as sendRequest, adapted to requestFunc
+func readRequest(ctx context.Context, httpClient *http.Client, auth Authenticator, method, URL string, header http.Header, body []byte) (response, *http.Response, error) {
+	resp, err := sendRequest(ctx, httpClient, auth, method, URL, header, body)
+	return resp, nil, err
+}
+
+// openRequest is delegated to execute the HTTP request using the given HTTP client.
+// The body of a successful response is left open in the returned *http.Response, the caller have to close it.
+// When the server answer with an error status code, the body is read and a *CloudantError is returned
+func openRequest(ctx context.Context, httpClient *http.Client, auth Authenticator, method, URL string, header http.Header, body []byte) (response, *http.Response, error) {
+	var resp response
+	req, err := http.NewRequestWithContext(ctx, method, URL, bytes.NewReader(body))
+	if err != nil {
+		zap.S().Error("sendRequest | Unable to create request! | Err: ", err)
+		return resp, nil, err
+	}
+	for key := range header {
+		req.Header[key] = header[key]
+	}
+	if auth != nil {
+		if err = auth.Authenticate(req); err != nil {
+			zap.S().Error("sendRequest | Unable to authenticate request! | Err: ", err)
+			return resp, nil, err
+		}
+	}
+	res, err := httpClient.Do(req)
+	if err != nil {
+		zap.S().Error("sendRequest | Error on response | Err: ", err)
+		return resp, nil, err
+	}
+	resp.StatusCode = res.StatusCode
+	resp.Header = res.Header
+	if resp.StatusCode >= 400 {
+		defer res.Body.Close()
+		if resp.Body, err = ioutil.ReadAll(res.Body); err != nil {
+			zap.S().Error("sendRequest | Unable to read response! | Err: ", err)
+			return resp, nil, fmt.Errorf("cloudant: unable to read response of %s %s: %w", method, URL, err)
+		}
+		return resp, nil, newCloudantError(method, URL, resp)
+	}
+	return resp, res, nil
+}
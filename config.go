@@ -0,0 +1,220 @@
+package cloudant
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// DefaultServiceName is the name used for look up the configuration when no service name is provided.
+// It is used as prefix of the environment variables (CLOUDANT_URL, CLOUDANT_APIKEY ...)
+const DefaultServiceName = "cloudant"
+
+// credentialsFileName is the default name of the IBM credentials file
+const credentialsFileName = "ibm-credentials.env"
+
+// vcapServiceLabel is the label used by Cloud Foundry for the Cloudant service
+const vcapServiceLabel = "cloudantNoSQLDB"
+
+// ErrConfNotFound is returned when the configuration is not present in the inspected source
+var ErrConfNotFound = errors.New("cloudant: configuration not found")
+
+// Validate is delegated to verify that the configuration contains the information needed by the client:
+// a valid URL of the instance and the credentials related to the authentication type
+func (conf Conf) Validate() error {
+	if _, err := conf.BaseURL(); err != nil {
+		return err
+	}
+	_, err := conf.authType()
+	return err
+}
+
+// LoadConf is delegated to load the configuration related to the given service, inspecting the following
+// sources in order and using the first one that contains the configuration:
+// 1. The IBM credentials file (see LoadConfFromCredentialsFile)
+// 2. The environment variables (see LoadConfFromEnv)
+// 3. The VCAP_SERVICES environment variable (see LoadConfFromVCAP)
+// serviceName: name of the service, DefaultServiceName if empty
+func LoadConf(serviceName string) (Conf, error) {
+	loaders := []struct {
+		name string
+		load func(string) (Conf, error)
+	}{
+		{"credentials file", func(name string) (Conf, error) { return LoadConfFromCredentialsFile("", name) }},
+		{"environment", LoadConfFromEnv},
+		{"VCAP_SERVICES", LoadConfFromVCAP},
+	}
+	for _, loader := range loaders {
+		conf, err := loader.load(serviceName)
+		if errors.Is(err, ErrConfNotFound) {
+			zap.S().Debug("LoadConf | Configuration not found in ", loader.name)
+			continue
+		}
+		if err != nil {
+			return conf, fmt.Errorf("cloudant: unable to load configuration from %s: %w", loader.name, err)
+		}
+		zap.S().Debug("LoadConf | Configuration loaded from ", loader.name)
+		return conf, nil
+	}
+	return Conf{}, ErrConfNotFound
+}
+
+// LoadConfFile is delegated to load the configuration from the given JSON file, like the conf.json in this repository
+func LoadConfFile(path string) (Conf, error) {
+	var conf Conf
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return conf, err
+	}
+	if err = json.Unmarshal(data, &conf); err != nil {
+		return conf, fmt.Errorf("cloudant: unable to decode %s: %w", path, err)
+	}
+	return conf, conf.Validate()
+}
+
+// LoadConfFromEnv is delegated to load the configuration from the environment variables.
+// The variables are prefixed by the service name in upper case (ex: CLOUDANT_URL, CLOUDANT_APIKEY):
+// URL, HOST, PORT, SCHEME, AUTH_TYPE, APIKEY, USERNAME, PASSWORD, AUTH_URL (IAM endpoint)
+// serviceName: name of the service, DefaultServiceName if empty
+func LoadConfFromEnv(serviceName string) (Conf, error) {
+	return confFromProperties(serviceName, os.LookupEnv)
+}
+
+// LoadConfFromCredentialsFile is delegated to load the configuration from a credentials file in the format used by the
+// IBM Cloud SDK, a list of KEY=VALUE with the same keys used by LoadConfFromEnv.
+// When path is empty, the file is searched in order in: the IBM_CREDENTIALS_FILE environment variable,
+// the working directory and the home directory of the user
+// serviceName: name of the service, DefaultServiceName if empty
+func LoadConfFromCredentialsFile(path, serviceName string) (Conf, error) {
+	if path == "" {
+		if path = findCredentialsFile(); path == "" {
+			return Conf{}, ErrConfNotFound
+		}
+	}
+	zap.S().Debug("LoadConfFromCredentialsFile | Reading credentials from [", path, "]")
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return Conf{}, err
+	}
+	properties := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if i := strings.Index(line, "="); i > 0 {
+			properties[strings.TrimSpace(line[:i])] = strings.TrimSpace(line[i+1:])
+		}
+	}
+	return confFromProperties(serviceName, func(key string) (string, bool) {
+		value, ok := properties[key]
+		return value, ok
+	})
+}
+
+// LoadConfFromVCAP is delegated to load the configuration from the VCAP_SERVICES environment variable set by Cloud Foundry.
+// The credentials of the service instance with the given name are used; if not found, the first instance bound
+// under the given label, or under the Cloudant label (cloudantNoSQLDB) is used
+// serviceName: name of the service, DefaultServiceName if empty
+func LoadConfFromVCAP(serviceName string) (Conf, error) {
+	var conf Conf
+	vcap, ok := os.LookupEnv("VCAP_SERVICES")
+	if !ok || strings.TrimSpace(vcap) == "" {
+		return conf, ErrConfNotFound
+	}
+	if serviceName == "" {
+		serviceName = DefaultServiceName
+	}
+	var services map[string][]struct {
+		Name        string          `json:"name"`
+		Credentials json.RawMessage `json:"credentials"`
+	}
+	if err := json.Unmarshal([]byte(vcap), &services); err != nil {
+		return conf, fmt.Errorf("cloudant: unable to decode VCAP_SERVICES: %w", err)
+	}
+	var credentials json.RawMessage
+	for _, instances := range services {
+		for _, instance := range instances {
+			if instance.Name == serviceName {
+				credentials = instance.Credentials
+			}
+		}
+	}
+	for _, label := range []string{serviceName, vcapServiceLabel} {
+		if instances := services[label]; credentials == nil && len(instances) > 0 {
+			credentials = instances[0].Credentials
+		}
+	}
+	if credentials == nil {
+		return conf, ErrConfNotFound
+	}
+	if err := json.Unmarshal(credentials, &conf); err != nil {
+		return conf, fmt.Errorf("cloudant: unable to decode credentials of %s: %w", serviceName, err)
+	}
+	return conf, conf.Validate()
+}
+
+// confFromProperties is delegated to initialize the configuration using the properties prefixed by the service name.
+// ErrConfNotFound is returned if none of the properties is present
+func confFromProperties(serviceName string, lookup func(string) (string, bool)) (Conf, error) {
+	var conf Conf
+	if serviceName == "" {
+		serviceName = DefaultServiceName
+	}
+	prefix := strings.ToUpper(strings.Replace(serviceName, "-", "_", -1)) + "_"
+	found := false
+	get := func(key string) string {
+		value, ok := lookup(prefix + key)
+		found = found || ok
+		return strings.TrimSpace(value)
+	}
+	conf.URL = get("URL")
+	conf.Host = get("HOST")
+	conf.Scheme = get("SCHEME")
+	conf.AuthType = get("AUTH_TYPE")
+	conf.Apikey = get("APIKEY")
+	conf.Username = get("USERNAME")
+	conf.Password = get("PASSWORD")
+	conf.IAMURL = get("AUTH_URL")
+	if port := get("PORT"); port != "" {
+		var err error
+		if conf.Port, err = strconv.Atoi(port); err != nil {
+			return conf, fmt.Errorf("%w: %sPORT is not a number", ErrInvalidArgument, prefix)
+		}
+	}
+	if !found {
+		return conf, ErrConfNotFound
+	}
+	return conf, conf.Validate()
+}
+
+// findCredentialsFile is delegated to search the IBM credentials file in the default locations.
+// An empty string is returned if the file does not exist
+func findCredentialsFile() string {
+	paths := []string{os.Getenv("IBM_CREDENTIALS_FILE")}
+	if wd, err := os.Getwd(); err == nil {
+		paths = append(paths, filepath.Join(wd, credentialsFileName))
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		paths = append(paths, filepath.Join(home, credentialsFileName))
+	}
+	for _, path := range paths {
+		if path == "" {
+			continue
+		}
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path
+		}
+	}
+	return ""
+}
@@ -0,0 +1,124 @@
+package cloudant
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// setEnv is delegated to set the given environment variables, returning the function for restore them
+func setEnv(vars map[string]string) func() {
+	old := make(map[string]*string)
+	for key, value := range vars {
+		if current, ok := os.LookupEnv(key); ok {
+			old[key] = &current
+		} else {
+			old[key] = nil
+		}
+		os.Setenv(key, value)
+	}
+	return func() {
+		for key, value := range old {
+			if value == nil {
+				os.Unsetenv(key)
+			} else {
+				os.Setenv(key, *value)
+			}
+		}
+	}
+}
+
+func TestLoadConfFile(t *testing.T) {
+	conf, err := LoadConfFile("conf.json")
+	if err != nil {
+		t.Error(err)
+	}
+	if conf.Port != 443 || conf.Username == "" {
+		t.Error("Unexpected configuration ", conf)
+	}
+	if _, err = LoadConfFile("not_exists.json"); !os.IsNotExist(err) {
+		t.Error("Expected not exists error, got ", err)
+	}
+}
+
+func TestLoadConfFromEnv(t *testing.T) {
+	defer setEnv(map[string]string{
+		"MY_DB_URL":      "http://localhost:5984",
+		"MY_DB_USERNAME": "admin",
+		"MY_DB_PASSWORD": "pass",
+		"MY_DB_PORT":     "5984",
+	})()
+	conf, err := LoadConfFromEnv("my-db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if conf.URL != "http://localhost:5984" || conf.Username != "admin" || conf.Port != 5984 {
+		t.Error("Unexpected configuration ", conf)
+	}
+	if _, err = LoadConfFromEnv("not-set"); !errors.Is(err, ErrConfNotFound) {
+		t.Error("Expected configuration not found, got ", err)
+	}
+	defer setEnv(map[string]string{"MY_DB_PORT": "abc"})()
+	if _, err = LoadConfFromEnv("my-db"); !errors.Is(err, ErrInvalidArgument) {
+		t.Error("Expected invalid port, got ", err)
+	}
+}
+
+func TestLoadConfFromCredentialsFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gocloudant")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "ibm-credentials.env")
+	content := "# Cloudant credentials\nCLOUDANT_URL=https://account.cloudant.com\nCLOUDANT_AUTH_TYPE=IAM\nCLOUDANT_APIKEY=secret=key\n"
+	if err = ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	conf, err := LoadConfFromCredentialsFile(path, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if conf.URL != "https://account.cloudant.com" || conf.Apikey != "secret=key" || conf.AuthType != "IAM" {
+		t.Error("Unexpected configuration ", conf)
+	}
+	// The file is found using IBM_CREDENTIALS_FILE
+	defer setEnv(map[string]string{"IBM_CREDENTIALS_FILE": path})()
+	if conf, err = LoadConf(""); err != nil || conf.Apikey != "secret=key" {
+		t.Error("Expected configuration from credentials file, got ", conf, err)
+	}
+}
+
+func TestLoadConfFromVCAP(t *testing.T) {
+	vcap := `{"cloudantNoSQLDB":[
+		{"name":"other","credentials":{"host":"other.cloudant.com","apikey":"other"}},
+		{"name":"my-cloudant","credentials":{"host":"account.cloudant.com","apikey":"key","port":443,"url":"https://account.cloudant.com"}}
+	]}`
+	defer setEnv(map[string]string{"VCAP_SERVICES": vcap})()
+	conf, err := LoadConfFromVCAP("my-cloudant")
+	if err != nil || conf.Host != "account.cloudant.com" || conf.Apikey != "key" {
+		t.Error("Unexpected configuration ", conf, err)
+	}
+	// Fallback to the first instance bound under the Cloudant label
+	if conf, err = LoadConfFromVCAP(""); err != nil || conf.Host != "other.cloudant.com" {
+		t.Error("Unexpected configuration ", conf, err)
+	}
+	// Environment variables take precedence over VCAP_SERVICES
+	defer setEnv(map[string]string{"CLOUDANT_URL": "http://localhost:5984", "CLOUDANT_AUTH_TYPE": "noauth"})()
+	if conf, err = LoadConf(""); err != nil || conf.URL != "http://localhost:5984" {
+		t.Error("Expected configuration from environment, got ", conf, err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := (Conf{Host: "localhost", AuthType: AuthTypeNoAuth}).Validate(); err != nil {
+		t.Error(err)
+	}
+	for _, conf := range []Conf{{AuthType: AuthTypeNoAuth}, {Host: "localhost"}, {Host: "localhost", AuthType: "unknown"}} {
+		if err := conf.Validate(); !errors.Is(err, ErrInvalidArgument) {
+			t.Error("Expected invalid argument for ", conf, " got ", err)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package cloudant
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// credentialRefreshTimeout is the maximum time that a background refresh of a credential can take
+const credentialRefreshTimeout = time.Minute
+
+// fetchCredential is delegated to request a new credential, together with its expiration.
+// A zero expiration means that the lifetime of the credential is unknown
+type fetchCredential func(ctx context.Context) (value string, expiration time.Time, err error)
+
+// cachedCredential is delegated to cache a credential that expire after a while (IAM token, session cookie).
+// The credential is cached until the 80% of its lifetime is elapsed, then a new one is requested in background while
+// the current one is still used. A credential that is alredy expired is refreshed before returning.
+// It is safe for concurrent use
+type cachedCredential struct {
+	fetch fetchCredential
+	// now is used for retrieve the current time, overridden during the test
+	now func() time.Time
+
+	// mu protect the credential and the related timing
+	mu         sync.Mutex
+	value      string
+	expiration time.Time
+	refreshAt  time.Time
+	refreshing bool
+
+	// refreshMu guarantee that only one request for a new credential is in flight
+	refreshMu sync.Mutex
+}
+
+// newCachedCredential is delegated to initialize a new cache that use the given method for retrieve the credential
+func newCachedCredential(fetch fetchCredential) *cachedCredential {
+	return &cachedCredential{fetch: fetch, now: time.Now}
+}
+
+// get is delegated to return a valid credential.
+// The cached one is returned when it is still valid, scheduling a refresh in background if it is going to expire
+func (cc *cachedCredential) get(ctx context.Context) (string, error) {
+	cc.mu.Lock()
+	value, expiration, refreshAt := cc.value, cc.expiration, cc.refreshAt
+	cc.mu.Unlock()
+
+	now := cc.now()
+	if value == "" || (!expiration.IsZero() && !now.Before(expiration)) {
+		zap.S().Debug("get | Credential not available or expired, requesting a new one ...")
+		return cc.refresh(ctx)
+	}
+	if !refreshAt.IsZero() && !now.Before(refreshAt) {
+		cc.refreshAsync()
+	}
+	return value, nil
+}
+
+// invalidate is delegated to discard the cached credential, a new one will be requested on the next call to get
+func (cc *cachedCredential) invalidate() {
+	cc.mu.Lock()
+	cc.value = ""
+	cc.mu.Unlock()
+}
+
+// expires is delegated to return the expiration of the cached credential
+func (cc *cachedCredential) expires() time.Time {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	return cc.expiration
+}
+
+// refreshAsync is delegated to request a new credential in background, if a refresh is not alredy running
+func (cc *cachedCredential) refreshAsync() {
+	cc.mu.Lock()
+	if cc.refreshing {
+		cc.mu.Unlock()
+		return
+	}
+	cc.refreshing = true
+	cc.mu.Unlock()
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), credentialRefreshTimeout)
+		defer cancel()
+		if _, err := cc.refresh(ctx); err != nil {
+			// The current credential is still valid, a new refresh will be scheduled by the next call to get
+			zap.S().Warn("refreshAsync | Unable to refresh credential in background | Err: ", err)
+		}
+		cc.mu.Lock()
+		cc.refreshing = false
+		cc.mu.Unlock()
+	}()
+}
+
+// refresh is delegated to request a new credential and save it into the cache.
+// If another goroutine refreshed the credential meanwhile, that one is returned without sending a new request
+func (cc *cachedCredential) refresh(ctx context.Context) (string, error) {
+	cc.refreshMu.Lock()
+	defer cc.refreshMu.Unlock()
+
+	cc.mu.Lock()
+	if cc.value != "" && (cc.refreshAt.IsZero() || cc.now().Before(cc.refreshAt)) {
+		value := cc.value
+		cc.mu.Unlock()
+		return value, nil
+	}
+	cc.mu.Unlock()
+
+	now := cc.now()
+	value, expiration, err := cc.fetch(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	cc.mu.Lock()
+	cc.value = value
+	cc.expiration = expiration
+	cc.refreshAt = time.Time{}
+	if !expiration.IsZero() {
+		// Refresh the credential when the 80% of its lifetime is elapsed
+		cc.refreshAt = expiration.Add(-expiration.Sub(now) / 5)
+	}
+	cc.mu.Unlock()
+	zap.S().Debug("refresh | New credential retrieved | Expiration: ", expiration)
+	return value, nil
+}
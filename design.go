@@ -0,0 +1,290 @@
+package cloudant
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// designPrefix is the prefix of the `_id` of every design document
+const designPrefix = `_design/`
+
+// View is delegated to save the map/reduce functions of a view
+// https://cloud.ibm.com/docs/services/Cloudant?topic=cloudant-creating-views-mapreduce
+type View struct {
+	// JavaScript function that emit the rows of the view
+	Map string `json:"map"`
+	// JavaScript function or built-in reduce (_count, _sum, _stats, _approx_count_distinct)
+	Reduce string `json:"reduce,omitempty"`
+}
+
+// SearchIndex is delegated to save the definition of a search index
+// https://cloud.ibm.com/docs/services/Cloudant?topic=cloudant-cloudant-search
+type SearchIndex struct {
+	// Analyzer used for the text, like "standard" or {"name": "perfield", "default": "english"}
+	Analyzer interface{} `json:"analyzer,omitempty"`
+	// JavaScript function that index the fields of the documents
+	Index string `json:"index"`
+}
+
+// DesignOptions is delegated to save the options of a design document
+type DesignOptions struct {
+	// True for the design documents of a partitioned DB that have to use partitioned indexes
+	Partitioned *bool `json:"partitioned,omitempty"`
+}
+
+// DesignDocument is delegated to save the views, the search indexes, the filters and the validation function of a DB.
+// The `_id` have to start with `_design/`, the prefix is added automatically when missing
+// https://cloud.ibm.com/docs/services/Cloudant?topic=cloudant-design-documents
+type DesignDocument struct {
+	Document
+	// Language of the functions, javascript if not set
+	Language string `json:"language,omitempty"`
+	// Views of the design document, indexed by name
+	Views map[string]View `json:"views,omitempty"`
+	// Search indexes of the design document, indexed by name
+	Indexes map[string]SearchIndex `json:"indexes,omitempty"`
+	// JavaScript functions used for filter the changes feed, indexed by name
+	Filters map[string]string `json:"filters,omitempty"`
+	// JavaScript function that validate every document written in the DB
+	ValidateDocUpdate string `json:"validate_doc_update,omitempty"`
+	// Options of the design document
+	Options *DesignOptions `json:"options,omitempty"`
+	// Other fields of the design document (ex: updates, shows, lists), saved as is
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// designDocument has the same fields of DesignDocument, it is used for encode them without the custom methods
+type designDocument DesignDocument
+
+// designFields contains the JSON fields mapped by DesignDocument, the other ones are saved in Extra
+var designFields = map[string]bool{
+	"_id": true, "_rev": true, "_deleted": true, "_attachments": true, "language": true, "views": true,
+	"indexes": true, "filters": true, "validate_doc_update": true, "options": true,
+}
+
+// MarshalJSON is delegated to encode the design document, including the Extra fields
+func (ddoc DesignDocument) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal(designDocument(ddoc))
+	if err != nil || len(ddoc.Extra) == 0 {
+		return data, err
+	}
+	var fields map[string]json.RawMessage
+	if err = json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	for name, value := range ddoc.Extra {
+		if !designFields[name] {
+			fields[name] = value
+		}
+	}
+	return json.Marshal(fields)
+}
+
+// UnmarshalJSON is delegated to decode the design document, saving the unknown fields in Extra
+func (ddoc *DesignDocument) UnmarshalJSON(data []byte) error {
+	var decoded designDocument
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	for name, value := range fields {
+		if !designFields[name] {
+			if decoded.Extra == nil {
+				decoded.Extra = make(map[string]json.RawMessage)
+			}
+			decoded.Extra[name] = value
+		}
+	}
+	*ddoc = DesignDocument(decoded)
+	return nil
+}
+
+// keepServerFields is delegated to return a copy of the design document that include the functions of the server copy
+// that are not declared: filters, validation and the Extra fields. The views and the search indexes are not merged
+func (ddoc DesignDocument) keepServerFields(current DesignDocument) DesignDocument {
+	if ddoc.Filters == nil {
+		ddoc.Filters = current.Filters
+	}
+	if ddoc.ValidateDocUpdate == "" {
+		ddoc.ValidateDocUpdate = current.ValidateDocUpdate
+	}
+	if len(current.Extra) > 0 {
+		extra := make(map[string]json.RawMessage, len(current.Extra)+len(ddoc.Extra))
+		for name, value := range current.Extra {
+			extra[name] = value
+		}
+		for name, value := range ddoc.Extra {
+			extra[name] = value
+		}
+		ddoc.Extra = extra
+	}
+	return ddoc
+}
+
+// designID is delegated to add the `_design/` prefix to the given name, if missing
+func designID(name string) string {
+	if strings.HasPrefix(name, designPrefix) {
+		return name
+	}
+	return designPrefix + name
+}
+
+// GetDesignDocument is delegated to retrieve the design document with the given name
+// dbName: DB that contains the design document
+// name: name of the design document, with or without the `_design/` prefix
+func (c *Client) GetDesignDocument(dbName, name string) (DesignDocument, error) {
+	return c.GetDesignDocumentContext(context.Background(), dbName, name)
+}
+
+// GetDesignDocumentContext is the same as GetDesignDocument, but the request is bound to the given context
+func (c *Client) GetDesignDocumentContext(ctx context.Context, dbName, name string) (DesignDocument, error) {
+	if name == "" {
+		return DesignDocument{}, fmt.Errorf("%w: name of the design document not provided", ErrInvalidArgument)
+	}
+	return GetContext[DesignDocument](ctx, c, dbName, designID(name))
+}
+
+// PutDesignDocument is delegated to create or update the given design document.
+// The `_rev` have to be the latest one when the design document alredy exists, the new one is written back
+// dbName: DB that contains the design document
+// ddoc: design document to save
+func (c *Client) PutDesignDocument(dbName string, ddoc *DesignDocument) (string, error) {
+	return c.PutDesignDocumentContext(context.Background(), dbName, ddoc)
+}
+
+// PutDesignDocumentContext is the same as PutDesignDocument, but the request is bound to the given context
+func (c *Client) PutDesignDocumentContext(ctx context.Context, dbName string, ddoc *DesignDocument) (string, error) {
+	if ddoc == nil || strings.TrimPrefix(ddoc.ID, designPrefix) == "" {
+		return "", fmt.Errorf("%w: `_id` of the design document not provided", ErrInvalidArgument)
+	}
+	ddoc.ID = designID(ddoc.ID)
+	zap.S().Debug("PutDesignDocument | Saving [", ddoc.ID, "] into DB [", dbName, "]")
+	return PutContext(ctx, c, dbName, ddoc)
+}
+
+// DeleteDesignDocument is delegated to delete the design document with the given name, and the related indexes
+// dbName: DB that contains the design document
+// name: name of the design document, with or without the `_design/` prefix
+// rev: latest revision of the design document
+func (c *Client) DeleteDesignDocument(dbName, name, rev string) error {
+	return c.DeleteDesignDocumentContext(context.Background(), dbName, name, rev)
+}
+
+// DeleteDesignDocumentContext is the same as DeleteDesignDocument, but the request is bound to the given context
+func (c *Client) DeleteDesignDocumentContext(ctx context.Context, dbName, name, rev string) error {
+	zap.S().Debug("DeleteDesignDocument | Deleting [", name, "] from DB [", dbName, "]")
+	if dbName == "" || name == "" || rev == "" {
+		return fmt.Errorf("%w: DB name, name and revision of the design document are mandatory", ErrInvalidArgument)
+	}
+	URL := c.docURL(dbName, designID(name)) + `?rev=` + url.QueryEscape(rev)
+	resp, err := c.send(ctx, `DELETE`, URL, newHeader(`Accept`, `application/json`), nil)
+	zap.S().Debug("DeleteDesignDocument | HTTP Code: ", resp.StatusCode)
+	return err
+}
+
+// CopyDesignDocument is delegated to copy a design document, like for build the new version of an index before
+// replacing the current one
+// dbName: DB that contains the design document
+// from: name of the design document to copy
+// to: name of the new design document
+// toRev: latest revision of the destination, when it alredy exists
+// Return the revision of the destination
+func (c *Client) CopyDesignDocument(dbName, from, to, toRev string) (string, error) {
+	return c.CopyDesignDocumentContext(context.Background(), dbName, from, to, toRev)
+}
+
+// CopyDesignDocumentContext is the same as CopyDesignDocument, but the request is bound to the given context
+func (c *Client) CopyDesignDocumentContext(ctx context.Context, dbName, from, to, toRev string) (string, error) {
+	zap.S().Debug("CopyDesignDocument | Copying [", from, "] to [", to, "] in DB [", dbName, "]")
+	if dbName == "" || from == "" || to == "" {
+		return "", fmt.Errorf("%w: DB name, source and destination are mandatory", ErrInvalidArgument)
+	}
+	destination := designID(to)
+	if toRev != "" {
+		destination += `?rev=` + url.QueryEscape(toRev)
+	}
+	headers := newHeader(`Accept`, `application/json`, `Destination`, destination)
+	resp, err := c.send(ctx, `COPY`, c.docURL(dbName, designID(from)), headers, nil)
+	zap.S().Debug("CopyDesignDocument | HTTP Code: ", resp.StatusCode)
+	if err != nil {
+		return "", err
+	}
+	result, err := decodeDocumentResponse(resp)
+	if err != nil {
+		return "", err
+	}
+	return result.Rev, nil
+}
+
+// SyncResult is delegated to save the outcome of SyncDesignDocs, as `_id` of the design documents
+type SyncResult struct {
+	Uploaded  []string
+	Unchanged []string
+}
+
+// sameDesign return true if the two design documents have the same definition, ignoring the revision and the attachments
+func sameDesign(a, b DesignDocument) bool {
+	a.Rev, b.Rev = "", ""
+	a.Attachments, b.Attachments = nil, nil
+	a.ID, b.ID = designID(a.ID), designID(b.ID)
+	x, _ := json.Marshal(a)
+	y, _ := json.Marshal(b)
+	var m, n interface{}
+	json.Unmarshal(x, &m)
+	json.Unmarshal(y, &n)
+	return reflect.DeepEqual(m, n)
+}
+
+// SyncDesignDocs is delegated to make the design documents of the DB match the given ones, that can be declared in the
+// code of the application. Only the design documents that are missing or different from the server copy are uploaded,
+// so it can be executed at every start without rebuild the indexes. The latest revision is written back into ddocs.
+// The views and the search indexes are replaced by the declared ones, while the filters, the validation function and
+// the Extra fields of the server copy are kept when they are not declared
+// dbName: DB that contains the design documents
+// ddocs: declared design documents, the `_id` is mandatory
+func (c *Client) SyncDesignDocs(dbName string, ddocs []DesignDocument) (SyncResult, error) {
+	return c.SyncDesignDocsContext(context.Background(), dbName, ddocs)
+}
+
+// SyncDesignDocsContext is the same as SyncDesignDocs, but the requests are bound to the given context
+func (c *Client) SyncDesignDocsContext(ctx context.Context, dbName string, ddocs []DesignDocument) (SyncResult, error) {
+	var result SyncResult
+	for i := range ddocs {
+		ddoc := &ddocs[i]
+		if strings.TrimPrefix(ddoc.ID, designPrefix) == "" {
+			return result, fmt.Errorf("%w: `_id` of the design document %d not provided", ErrInvalidArgument, i)
+		}
+		ddoc.ID = designID(ddoc.ID)
+		current, err := c.GetDesignDocumentContext(ctx, dbName, ddoc.ID)
+		if err != nil && !IsNotFound(err) {
+			return result, err
+		}
+		merged := *ddoc
+		if err == nil {
+			merged = ddoc.keepServerFields(current)
+			if sameDesign(merged, current) {
+				zap.S().Debug("SyncDesignDocs | [", ddoc.ID, "] is up to date")
+				ddoc.Rev = current.Rev
+				result.Unchanged = append(result.Unchanged, ddoc.ID)
+				continue
+			}
+		}
+		merged.Rev = current.Rev
+		zap.S().Info("SyncDesignDocs | Uploading [", ddoc.ID, "] into DB [", dbName, "]")
+		if _, err = c.PutDesignDocumentContext(ctx, dbName, &merged); err != nil {
+			return result, err
+		}
+		ddoc.Rev = merged.Rev
+		result.Uploaded = append(result.Uploaded, ddoc.ID)
+	}
+	return result, nil
+}
@@ -0,0 +1,152 @@
+package cloudant
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// newTestDesignServer is delegated to initialize a fake instance that store the design documents of `test_db` in memory.
+// Every write is counted in writes
+func newTestDesignServer(ddocs map[string]json.RawMessage, writes *int) *httptest.Server {
+	revision := func(doc json.RawMessage) int {
+		var d Document
+		json.Unmarshal(doc, &d)
+		n, _ := strconv.Atoi(strings.Split(d.Rev, "-")[0])
+		return n
+	}
+	save := func(w http.ResponseWriter, id string, doc map[string]interface{}, rev int) {
+		*writes++
+		doc["_id"], doc["_rev"] = id, strconv.Itoa(rev+1)+"-x"
+		ddocs[id], _ = json.Marshal(doc)
+		w.WriteHeader(201)
+		json.NewEncoder(w).Encode(DocumentResponse{OK: true, ID: id, Rev: doc["_rev"].(string)})
+	}
+	return newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		id := strings.TrimPrefix(r.URL.Path, "/test_db/")
+		current, exists := ddocs[id]
+		rev := revision(current)
+		switch r.Method {
+		case "GET":
+			if !exists {
+				w.WriteHeader(404)
+				return
+			}
+			w.Write(current)
+		case "PUT":
+			var doc map[string]interface{}
+			body, _ := ioutil.ReadAll(r.Body)
+			json.Unmarshal(body, &doc)
+			if exists && doc["_rev"] != strconv.Itoa(rev)+"-x" {
+				w.WriteHeader(409)
+				return
+			}
+			save(w, id, doc, rev)
+		case "DELETE":
+			if !exists || r.URL.Query().Get("rev") != strconv.Itoa(rev)+"-x" {
+				w.WriteHeader(409)
+				return
+			}
+			delete(ddocs, id)
+			w.Write([]byte(`{"ok":true}`))
+		case "COPY":
+			var doc map[string]interface{}
+			json.Unmarshal(current, &doc)
+			destination := strings.Split(r.Header.Get("Destination"), "?rev=")
+			_, destExists := ddocs[destination[0]]
+			destRev := revision(ddocs[destination[0]])
+			if !exists || destExists && (len(destination) < 2 || destination[1] != strconv.Itoa(destRev)+"-x") {
+				w.WriteHeader(409)
+				return
+			}
+			save(w, destination[0], doc, destRev)
+		}
+	})
+}
+
+func TestDesignDocument(t *testing.T) {
+	writes := 0
+	srv := newTestDesignServer(map[string]json.RawMessage{}, &writes)
+	defer srv.Close()
+	c := newTestClient(srv)
+	ddoc := DesignDocument{
+		Document: Document{ID: "users"},
+		Views:    map[string]View{"by_name": {Map: "function(doc) { emit(doc.name, 1) }", Reduce: "_count"}},
+		Options:  &DesignOptions{Partitioned: Bool(false)},
+	}
+	if rev, err := c.PutDesignDocument("test_db", &ddoc); err != nil || rev != "1-x" || ddoc.ID != "_design/users" {
+		t.Fatal("Unexpected result ", rev, err, ddoc.ID)
+	}
+	stored, err := c.GetDesignDocument("test_db", "users")
+	if err != nil || stored.Rev != "1-x" || stored.Views["by_name"].Reduce != "_count" || *stored.Options.Partitioned {
+		t.Error("Unexpected design document ", stored, err)
+	}
+	if rev, err := c.CopyDesignDocument("test_db", "users", "_design/users_new", ""); err != nil || rev != "1-x" {
+		t.Error("Unexpected copy ", rev, err)
+	}
+	if rev, err := c.CopyDesignDocument("test_db", "users", "users_new", "1-x"); err != nil || rev != "2-x" {
+		t.Error("Unexpected copy over existing destination ", rev, err)
+	}
+	if err = c.DeleteDesignDocument("test_db", "users", "1-x"); err != nil {
+		t.Error(err)
+	}
+	if _, err = c.GetDesignDocument("test_db", "_design/users"); !IsNotFound(err) {
+		t.Error("Expected not found, got ", err)
+	}
+}
+
+func TestSyncDesignDocs(t *testing.T) {
+	writes := 0
+	srv := newTestDesignServer(map[string]json.RawMessage{
+		"_design/same":    json.RawMessage(`{"_id":"_design/same","_rev":"3-x","views":{"v":{"map":"function(doc) {}"}}}`),
+		"_design/changed": json.RawMessage(`{"_id":"_design/changed","_rev":"1-x","views":{"v":{"map":"function(doc) {}"}}}`),
+	}, &writes)
+	defer srv.Close()
+	c := newTestClient(srv)
+	ddocs := []DesignDocument{
+		{Document: Document{ID: "same"}, Views: map[string]View{"v": {Map: "function(doc) {}"}}},
+		{Document: Document{ID: "_design/changed"}, Views: map[string]View{"v": {Map: "function(doc) { emit(doc._id) }"}}},
+		{Document: Document{ID: "new"}, ValidateDocUpdate: "function(newDoc, oldDoc, userCtx) {}"},
+	}
+	result, err := c.SyncDesignDocs("test_db", ddocs)
+	if err != nil || strings.Join(result.Uploaded, ",") != "_design/changed,_design/new" || strings.Join(result.Unchanged, ",") != "_design/same" {
+		t.Fatal("Unexpected result ", result, err)
+	}
+	if writes != 2 || ddocs[0].Rev != "3-x" || ddocs[1].Rev != "2-x" || ddocs[2].Rev != "1-x" {
+		t.Error("Unexpected revisions ", writes, ddocs[0].Rev, ddocs[1].Rev, ddocs[2].Rev)
+	}
+	if result, err = c.SyncDesignDocs("test_db", ddocs); err != nil || len(result.Uploaded) != 0 || writes != 2 {
+		t.Error("Expected nothing uploaded, got ", result, err)
+	}
+}
+
+func TestSyncDesignDocsKeepFunctions(t *testing.T) {
+	writes := 0
+	stored := map[string]json.RawMessage{
+		"_design/app": json.RawMessage(`{"_id":"_design/app","_rev":"1-x","views":{"v":{"map":"function(doc) {}"}},
+			"filters":{"by_type":"function(doc, req) { return doc.type == req.query.type }"},"updates":{"touch":"function(doc, req) {}"}}`),
+	}
+	srv := newTestDesignServer(stored, &writes)
+	defer srv.Close()
+	c := newTestClient(srv)
+	// The filter and the update function are not declared, but they are on the server
+	ddocs := []DesignDocument{{Document: Document{ID: "app"}, Views: map[string]View{"v": {Map: "function(doc) {}"}}}}
+	if result, err := c.SyncDesignDocs("test_db", ddocs); err != nil || len(result.Unchanged) != 1 || writes != 0 {
+		t.Fatal("Expected design document unchanged, got ", result, err)
+	}
+	ddocs[0].Views["v"] = View{Map: "function(doc) { emit(doc._id) }"}
+	if result, err := c.SyncDesignDocs("test_db", ddocs); err != nil || len(result.Uploaded) != 1 || writes != 1 || ddocs[0].Rev != "2-x" {
+		t.Fatal("Expected design document uploaded, got ", result, err)
+	}
+	ddoc, err := c.GetDesignDocument("test_db", "app")
+	if err != nil || ddoc.Filters["by_type"] == "" || string(ddoc.Extra["updates"]) != `{"touch":"function(doc, req) {}"}` || ddoc.Views["v"].Map != "function(doc) { emit(doc._id) }" {
+		t.Error("Expected filters and updates preserved, got ", ddoc, err)
+	}
+	if ddocs[0].Filters != nil || ddocs[0].Extra != nil {
+		t.Error("The declared design document should not be modified, got ", ddocs[0])
+	}
+}
@@ -0,0 +1,117 @@
+package cloudant
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// maxDocumentSize is the maximum size of a single document accepted by Cloudant
+const maxDocumentSize = 1048576
+
+// DocumentResponse is delegated to save the response returned by Cloudant after a write of a document
+// https://cloud.ibm.com/docs/services/Cloudant?topic=cloudant-documents#create-document
+type DocumentResponse struct {
+	OK  bool   `json:"ok"`
+	ID  string `json:"id"`
+	Rev string `json:"rev"`
+}
+
+// UpdateOption is delegated to customize the update of a document
+type UpdateOption func(*updateOptions)
+
+type updateOptions struct {
+	ifMatch bool
+}
+
+// RevInIfMatch is delegated to send the revision of the document in the If-Match header,
+// instead of the `rev` query parameter
+func RevInIfMatch() UpdateOption {
+	return func(opts *updateOptions) {
+		opts.ifMatch = true
+	}
+}
+
+// marshalDocument is delegated to encode the given document.
+// []byte, json.RawMessage and string are considered alredy encoded and are sent as is
+func marshalDocument(doc interface{}) ([]byte, error) {
+	var data []byte
+	switch v := doc.(type) {
+	case []byte:
+		data = v
+	case json.RawMessage:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		var err error
+		if data, err = json.Marshal(doc); err != nil {
+			return nil, fmt.Errorf("cloudant: unable to encode document: %w", err)
+		}
+	}
+	if len(data) >= maxDocumentSize {
+		return nil, ErrDocumentTooLarge
+	}
+	return data, nil
+}
+
+// databaseURL is delegated to compose the URL of the given DB, escaping the name
+func (c *Client) databaseURL(dbName string) string {
+	return c.dbURL + `/` + escapeSegment(dbName)
+}
+
+// escapeSegment is delegated to escape a segment of the path. CouchDB decodes `+` as a space, so it is escaped too
+func escapeSegment(segment string) string {
+	return strings.ReplaceAll(url.PathEscape(segment), "+", "%2B")
+}
+
+// docURL is delegated to compose the URL of the given document, escaping the DB name and the `_id`.
+// The slash after the `_design` and `_local` prefix is preserved
+func (c *Client) docURL(dbName, id string) string {
+	for _, prefix := range []string{"_design/", "_local/"} {
+		if strings.HasPrefix(id, prefix) {
+			return c.databaseURL(dbName) + `/` + prefix + escapeSegment(id[len(prefix):])
+		}
+	}
+	return c.databaseURL(dbName) + `/` + escapeSegment(id)
+}
+
+// designURL is delegated to compose the URL of a view or a search index (kind is `_view` or `_search`),
+// for the whole DB or for a partition. The design document can have the `_design/` prefix
+func (c *Client) designURL(dbName, partition, ddoc, kind, name string) string {
+	base := c.databaseURL(dbName)
+	if partition != "" {
+		base += `/_partition/` + escapeSegment(partition)
+	}
+	return base + `/` + designPrefix + escapeSegment(strings.TrimPrefix(ddoc, designPrefix)) + `/` + kind + `/` + escapeSegment(name)
+}
+
+// decodeDocumentResponse is delegated to decode the response of a write
+func decodeDocumentResponse(resp response) (DocumentResponse, error) {
+	var result DocumentResponse
+	if err := json.Unmarshal(resp.Body, &result); err != nil {
+		return result, fmt.Errorf("cloudant: unable to decode response: %w", err)
+	}
+	return result, nil
+}
+
+// revHeader is delegated to initialize the headers of a write, sending the revision in the If-Match header if requested.
+// Return the query parameters that have to be used for the request
+func revHeader(rev string, opts []UpdateOption) (http.Header, url.Values) {
+	var options updateOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	headers := newHeader(`Accept`, `application/json`, `Content-Type`, `application/json`)
+	query := url.Values{}
+	if rev != "" {
+		if options.ifMatch {
+			headers.Set("If-Match", rev)
+		} else {
+			query.Set("rev", rev)
+		}
+	}
+	return headers, query
+}
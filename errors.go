@@ -0,0 +1,105 @@
+package cloudant
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// ErrInvalidArgument is returned when a mandatory parameter is missing or not valid.
+// The request is not sent to Cloudant
+var ErrInvalidArgument = errors.New("cloudant: invalid argument")
+
+// ErrDocumentTooLarge is returned when a document exceed the 1MB limit imposed by Cloudant
+var ErrDocumentTooLarge = errors.New("cloudant: document exceed the 1MB limit")
+
+// CloudantError is delegated to save the information related to a request rejected by Cloudant.
+// It is returned every time that the server answer with an HTTP status code greater than 399
+// https://cloud.ibm.com/docs/services/Cloudant?topic=cloudant-http#http-status-codes
+type CloudantError struct {
+	// HTTP status code returned by the server
+	StatusCode int `json:"-"`
+	// Error returned in the `error` field of the body
+	Err string `json:"error"`
+	// Reason returned in the `reason` field of the body
+	Reason string `json:"reason"`
+	// Request ID returned in the X-Couch-Request-ID header
+	RequestID string `json:"-"`
+	// HTTP method of the failed request
+	Method string `json:"-"`
+	// URL of the failed request
+	URL string `json:"-"`
+}
+
+// Error is delegated to return a human readable description of the error
+func (e *CloudantError) Error() string {
+	msg := fmt.Sprintf("cloudant: %s %s: %d %s", e.Method, e.URL, e.StatusCode, http.StatusText(e.StatusCode))
+	if e.Err != "" {
+		msg += " | " + e.Err
+	}
+	if e.Reason != "" {
+		msg += ": " + e.Reason
+	}
+	if e.RequestID != "" {
+		msg += " (request id: " + e.RequestID + ")"
+	}
+	return msg
+}
+
+// newCloudantError is delegated to initialize a CloudantError from the given response.
+// The `error` and `reason` fields are extracted from the body when available
+func newCloudantError(method, URL string, resp response) *CloudantError {
+	e := &CloudantError{StatusCode: resp.StatusCode, Method: method, URL: URL}
+	// The body can be empty (HEAD request) or not a JSON, the status code is enough in that case
+	json.Unmarshal(resp.Body, e)
+	if resp.Header != nil {
+		e.RequestID = resp.Header.Get("X-Couch-Request-ID")
+	}
+	return e
+}
+
+// StatusCode is delegated to extract the HTTP status code from the given error.
+// 0 is returned if the error is not related to a response sent by Cloudant
+func StatusCode(err error) int {
+	var e *CloudantError
+	if errors.As(err, &e) {
+		return e.StatusCode
+	}
+	return 0
+}
+
+// IsBadRequest return true if the error is related to a malformed request (400)
+func IsBadRequest(err error) bool {
+	return StatusCode(err) == http.StatusBadRequest
+}
+
+// IsUnauthorized return true if the error is related to missing or expired credentials (401)
+func IsUnauthorized(err error) bool {
+	return StatusCode(err) == http.StatusUnauthorized
+}
+
+// IsForbidden return true if the credentials have not the permission for execute the request (403)
+func IsForbidden(err error) bool {
+	return StatusCode(err) == http.StatusForbidden
+}
+
+// IsNotFound return true if the database or the document does not exist (404)
+func IsNotFound(err error) bool {
+	return StatusCode(err) == http.StatusNotFound
+}
+
+// IsConflict return true if the document was modified by someone else, or the `_rev` is not the latest (409)
+func IsConflict(err error) bool {
+	return StatusCode(err) == http.StatusConflict
+}
+
+// IsPreconditionFailed return true if the resource alredy exists, like during the creation of a DB (412)
+func IsPreconditionFailed(err error) bool {
+	return StatusCode(err) == http.StatusPreconditionFailed
+}
+
+// IsRateLimited return true if the request was rejected due to the throughput capacity of the instance (429)
+func IsRateLimited(err error) bool {
+	return StatusCode(err) == http.StatusTooManyRequests
+}
@@ -0,0 +1,42 @@
+package cloudant
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestCloudantError(t *testing.T) {
+	resp := response{
+		StatusCode: 409,
+		Body:       []byte(`{"error":"conflict","reason":"Document update conflict."}`),
+		Header:     http.Header{"X-Couch-Request-Id": []string{"abc123"}},
+	}
+	err := newCloudantError("PUT", "https://host/db/doc", resp)
+	if err.Err != "conflict" || err.Reason != "Document update conflict." || err.RequestID != "abc123" {
+		t.Error("Unexpected error details ", err)
+	}
+	t.Log(err.Error())
+	wrapped := fmt.Errorf("wrapped: %w", err)
+	if !IsConflict(wrapped) || IsNotFound(wrapped) || StatusCode(wrapped) != 409 {
+		t.Fail()
+	}
+	// Body without JSON (HEAD request)
+	err = newCloudantError("HEAD", "https://host/db", response{StatusCode: 404})
+	if !IsNotFound(err) || err.Err != "" {
+		t.Fail()
+	}
+}
+
+func TestStatusCode(t *testing.T) {
+	if StatusCode(errors.New("network error")) != 0 || StatusCode(nil) != 0 {
+		t.Fail()
+	}
+	if !IsRateLimited(&CloudantError{StatusCode: 429}) {
+		t.Fail()
+	}
+	if !IsPreconditionFailed(&CloudantError{StatusCode: 412}) {
+		t.Fail()
+	}
+}
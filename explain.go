@@ -0,0 +1,138 @@
+package cloudant
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"go.uber.org/zap"
+)
+
+// ErrFullScan is returned by RequireIndex when the query is executed reading every document of the DB
+var ErrFullScan = errors.New("cloudant: query executed with a full scan of _all_docs")
+
+// ExplainRange is delegated to save the range of the index read by the query
+type ExplainRange struct {
+	StartKey    json.RawMessage `json:"start_key"`
+	EndKey      json.RawMessage `json:"end_key"`
+	Direction   string          `json:"direction"`
+	IncludeDocs bool            `json:"include_docs"`
+	ViewType    string          `json:"view_type"`
+	Reduce      bool            `json:"reduce"`
+	Stable      bool            `json:"stable"`
+	Update      interface{}     `json:"update"`
+	Conflicts   interface{}     `json:"conflicts"`
+}
+
+// IndexCandidate is delegated to save an index evaluated for the query, and why it was (not) chosen
+type IndexCandidate struct {
+	Index    Index `json:"index"`
+	Analysis struct {
+		Usable  bool `json:"usable"`
+		Reasons []struct {
+			Name string `json:"name"`
+		} `json:"reasons"`
+		Ranking  int  `json:"ranking"`
+		Covering bool `json:"covering"`
+	} `json:"analysis"`
+}
+
+// ExplainResult is delegated to save how Cloudant execute a query
+// https://cloud.ibm.com/docs/services/Cloudant?topic=cloudant-query#explain-plans
+type ExplainResult struct {
+	// DB queried
+	DBName string `json:"dbname"`
+	// Index chosen for execute the query
+	Index Index `json:"index"`
+	// Indexes evaluated, only on the instances that support it
+	Candidates []IndexCandidate `json:"index_candidates,omitempty"`
+	// Selector as interpreted by Cloudant
+	Selector Selector `json:"selector"`
+	// Options of the query
+	Opts  map[string]interface{} `json:"opts"`
+	Limit int                    `json:"limit"`
+	Skip  int                    `json:"skip"`
+	// Fields returned, `all_fields` or the list of fields
+	Fields json.RawMessage `json:"fields"`
+	// Range of the index read by the query
+	Range ExplainRange `json:"mrargs"`
+	// True if the index contains every field requested, so the documents are not read
+	Covering bool `json:"covering,omitempty"`
+}
+
+// IsFullScan return true if no index is usable for the query, so every document of the DB is read
+func (r ExplainResult) IsFullScan() bool {
+	return r.Index.Type == indexTypeSpecial && r.Index.Name == "_all_docs"
+}
+
+// Warnings is delegated to return the problems found in the execution plan: the full scan of the DB and
+// the reasons that exclude the other indexes
+func (r ExplainResult) Warnings() []string {
+	var warnings []string
+	if r.IsFullScan() {
+		warnings = append(warnings, "no matching index found, every document of "+r.DBName+" is read")
+	}
+	for _, candidate := range r.Candidates {
+		for _, reason := range candidate.Analysis.Reasons {
+			warnings = append(warnings, fmt.Sprintf("index %s/%s not used: %s", candidate.Index.ddocName(), candidate.Index.Name, reason.Name))
+		}
+	}
+	return warnings
+}
+
+// Explain is delegated to retrieve the execution plan of the given query, without execute it
+// https://cloud.ibm.com/docs/services/Cloudant?topic=cloudant-query#explain-plans
+// dbName: DB that we want to query
+// selector: condition that the documents have to match
+// opts: the same options used with Find
+func (c *Client) Explain(dbName string, selector Selector, opts FindOptions) (ExplainResult, error) {
+	return c.ExplainContext(context.Background(), dbName, selector, opts)
+}
+
+// ExplainContext is the same as Explain, but the request is bound to the given context
+func (c *Client) ExplainContext(ctx context.Context, dbName string, selector Selector, opts FindOptions) (ExplainResult, error) {
+	var result ExplainResult
+	zap.S().Debug("Explain | Explaining query on DB [", dbName, "] ...")
+	if dbName == "" {
+		return result, fmt.Errorf("%w: DB name not provided", ErrInvalidArgument)
+	}
+	body, err := json.Marshal(newFindRequest(selector, opts))
+	if err != nil {
+		return result, fmt.Errorf("%w: unable to encode query: %v", ErrInvalidArgument, err)
+	}
+	URL := c.findURL(dbName, opts.Partition, `_explain`)
+	headers := newHeader(`Accept`, `application/json`, `Content-Type`, `application/json`)
+	zap.S().Debug("Explain | Sending request to URL: [", URL, "] | Query: ", string(body))
+	resp, err := c.send(ctx, `POST`, URL, headers, body)
+	zap.S().Debug("Explain | HTTP Code: ", resp.StatusCode)
+	if err != nil {
+		return result, err
+	}
+	if err = json.Unmarshal(resp.Body, &result); err != nil {
+		return result, fmt.Errorf("cloudant: unable to decode response: %w", err)
+	}
+	return result, nil
+}
+
+// RequireIndex is delegated to verify that the given query is executed using an index.
+// ErrFullScan is returned if the query read every document of the DB, it is meant to be used in the tests
+// dbName: DB that we want to query
+// selector: condition that the documents have to match
+// opts: the same options used with Find
+func (c *Client) RequireIndex(dbName string, selector Selector, opts FindOptions) error {
+	return c.RequireIndexContext(context.Background(), dbName, selector, opts)
+}
+
+// RequireIndexContext is the same as RequireIndex, but the request is bound to the given context
+func (c *Client) RequireIndexContext(ctx context.Context, dbName string, selector Selector, opts FindOptions) error {
+	result, err := c.ExplainContext(ctx, dbName, selector, opts)
+	if err != nil {
+		return err
+	}
+	if result.IsFullScan() {
+		data, _ := json.Marshal(result.Selector)
+		return fmt.Errorf("%w: %s", ErrFullScan, data)
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package cloudant
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"testing"
+)
+
+func TestExplain(t *testing.T) {
+	srv := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		var req findRequest
+		body, _ := ioutil.ReadAll(r.Body)
+		if r.URL.Path != "/test_db/_explain" || json.Unmarshal(body, &req) != nil {
+			w.WriteHeader(400)
+			return
+		}
+		if _, ok := req.Selector["name"]; ok {
+			w.Write([]byte(`{"dbname":"test_db","index":{"ddoc":"_design/idx","name":"by_name","type":"json","def":{"fields":[{"name":"asc"}]}},
+				"selector":{"name":{"$eq":"mario"}},"opts":{"r":[49]},"limit":25,"skip":0,"fields":"all_fields",
+				"mrargs":{"start_key":["mario"],"end_key":["mario","<MAX>"],"direction":"fwd","include_docs":true,"view_type":"map"}}`))
+			return
+		}
+		w.Write([]byte(`{"dbname":"test_db","index":{"ddoc":null,"name":"_all_docs","type":"special","def":{"fields":[{"_id":"asc"}]}},
+			"index_candidates":[{"index":{"ddoc":"_design/idx","name":"by_name","type":"json","def":{"fields":[{"name":"asc"}]}},
+			"analysis":{"usable":false,"reasons":[{"name":"field_mismatch"}],"ranking":2,"covering":false}}],
+			"selector":{"age":{"$gt":18}},"opts":{},"limit":25,"skip":0,"fields":"all_fields","mrargs":{"start_key":null,"end_key":"<MAX>","direction":"fwd"}}`))
+	})
+	defer srv.Close()
+	c := newTestClient(srv)
+	result, err := c.Explain("test_db", Field("name").Eq("mario"), FindOptions{})
+	if err != nil || result.Index.Name != "by_name" || result.IsFullScan() || string(result.Range.StartKey) != `["mario"]` || len(result.Warnings()) != 0 {
+		t.Error("Unexpected plan ", result, err)
+	}
+	if err = c.RequireIndex("test_db", Field("name").Eq("mario"), FindOptions{}); err != nil {
+		t.Error(err)
+	}
+	if result, err = c.Explain("test_db", Field("age").Gt(18), FindOptions{}); err != nil || !result.IsFullScan() || len(result.Warnings()) != 2 {
+		t.Error("Expected full scan, got ", result, result.Warnings(), err)
+	}
+	if err = c.RequireIndex("test_db", Field("age").Gt(18), FindOptions{}); !errors.Is(err, ErrFullScan) {
+		t.Error("Expected full scan error, got ", err)
+	}
+}
@@ -0,0 +1,178 @@
+package cloudant
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"go.uber.org/zap"
+)
+
+// Sort is delegated to save the sort order of a field in a query
+type Sort struct {
+	// Name of the field
+	Field string
+	// Sort from the greater to the lower value
+	Descending bool
+}
+
+// Asc is delegated to sort the results by the given field, from the lower to the greater value
+func Asc(field string) Sort {
+	return Sort{Field: field}
+}
+
+// Desc is delegated to sort the results by the given field, from the greater to the lower value
+func Desc(field string) Sort {
+	return Sort{Field: field, Descending: true}
+}
+
+// MarshalJSON is delegated to encode the sort as {"field": "asc|desc"}
+func (s Sort) MarshalJSON() ([]byte, error) {
+	direction := "asc"
+	if s.Descending {
+		direction = "desc"
+	}
+	return json.Marshal(map[string]string{s.Field: direction})
+}
+
+// FindOptions is delegated to customize the query executed by Find
+// https://cloud.ibm.com/docs/services/Cloudant?topic=cloudant-query#finding-documents-by-using-an-index
+type FindOptions struct {
+	// Fields returned for every document, every field if empty
+	Fields []string
+	// Sort order of the results, the fields have to be indexed
+	Sort []Sort
+	// Maximum number of results, 25 (the Cloudant default) if not set
+	Limit int
+	// Number of results to skip
+	Skip int
+	// Design document of the index to use, with or without the `_design/` prefix
+	UseIndex string
+	// Name of the index in the UseIndex design document
+	IndexName string
+	// Number of replicas that have to be read for every document, 1 if not set
+	R int
+	// Bookmark returned by a previous query, for request the next page of results
+	Bookmark string
+	// Return the statistics related to the execution of the query
+	ExecutionStats bool
+	// Return the conflicted revisions of the documents
+	Conflicts bool
+	// Execute the query only on the given partition of a partitioned DB
+	Partition string
+}
+
+// findRequest is the body of a `_find` request
+type findRequest struct {
+	Selector       Selector    `json:"selector"`
+	Fields         []string    `json:"fields,omitempty"`
+	Sort           []Sort      `json:"sort,omitempty"`
+	Limit          int         `json:"limit,omitempty"`
+	Skip           int         `json:"skip,omitempty"`
+	UseIndex       interface{} `json:"use_index,omitempty"`
+	R              int         `json:"r,omitempty"`
+	Bookmark       string      `json:"bookmark,omitempty"`
+	ExecutionStats bool        `json:"execution_stats,omitempty"`
+	Conflicts      bool        `json:"conflicts,omitempty"`
+}
+
+// newFindRequest is delegated to compose the body of a `_find` request
+func newFindRequest(selector Selector, opts FindOptions) findRequest {
+	if selector == nil {
+		selector = Selector{}
+	}
+	req := findRequest{
+		Selector:       selector,
+		Fields:         opts.Fields,
+		Sort:           opts.Sort,
+		Limit:          opts.Limit,
+		Skip:           opts.Skip,
+		R:              opts.R,
+		Bookmark:       opts.Bookmark,
+		ExecutionStats: opts.ExecutionStats,
+		Conflicts:      opts.Conflicts,
+	}
+	if opts.UseIndex != "" {
+		if opts.IndexName != "" {
+			req.UseIndex = []string{opts.UseIndex, opts.IndexName}
+		} else {
+			req.UseIndex = opts.UseIndex
+		}
+	}
+	return req
+}
+
+// ExecutionStats is delegated to save the statistics related to the execution of a query
+type ExecutionStats struct {
+	TotalKeysExamined       int     `json:"total_keys_examined"`
+	TotalDocsExamined       int     `json:"total_docs_examined"`
+	TotalQuorumDocsExamined int     `json:"total_quorum_docs_examined"`
+	ResultsReturned         int     `json:"results_returned"`
+	ExecutionTimeMs         float64 `json:"execution_time_ms"`
+}
+
+// FindResult is delegated to save the response of a `_find` request
+type FindResult struct {
+	// Documents that match the selector
+	Docs []json.RawMessage `json:"docs"`
+	// Bookmark to use for request the next page of results
+	Bookmark string `json:"bookmark,omitempty"`
+	// Warning returned by Cloudant, like when no index is available for the query
+	Warning string `json:"warning,omitempty"`
+	// Statistics of the query, only with ExecutionStats
+	ExecutionStats *ExecutionStats `json:"execution_stats,omitempty"`
+}
+
+// Decode is delegated to decode the documents into the given pointer to slice
+func (r FindResult) Decode(v interface{}) error {
+	data, err := json.Marshal(r.Docs)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// findURL is delegated to compose the URL of the `_find` endpoint, for the whole DB or for a partition
+func (c *Client) findURL(dbName, partition, endpoint string) string {
+	if partition != "" {
+		return c.databaseURL(dbName) + `/_partition/` + escapeSegment(partition) + `/` + endpoint
+	}
+	return c.databaseURL(dbName) + `/` + endpoint
+}
+
+// Find is delegated to retrieve the documents that match the given selector using Cloudant Query
+// https://cloud.ibm.com/docs/services/Cloudant?topic=cloudant-query#finding-documents-by-using-an-index
+// dbName: DB that we want to query
+// selector: condition that the documents have to match, see Field for build it
+// opts: fields, sort and paging of the results
+func (c *Client) Find(dbName string, selector Selector, opts FindOptions) (FindResult, error) {
+	return c.FindContext(context.Background(), dbName, selector, opts)
+}
+
+// FindContext is the same as Find, but the request is bound to the given context
+func (c *Client) FindContext(ctx context.Context, dbName string, selector Selector, opts FindOptions) (FindResult, error) {
+	var result FindResult
+	zap.S().Debug("Find | Querying DB [", dbName, "] ...")
+	if dbName == "" {
+		return result, fmt.Errorf("%w: DB name not provided", ErrInvalidArgument)
+	}
+	body, err := json.Marshal(newFindRequest(selector, opts))
+	if err != nil {
+		return result, fmt.Errorf("%w: unable to encode query: %v", ErrInvalidArgument, err)
+	}
+	URL := c.findURL(dbName, opts.Partition, `_find`)
+	headers := newHeader(`Accept`, `application/json`, `Content-Type`, `application/json`)
+	zap.S().Debug("Find | Sending request to URL: [", URL, "] | Query: ", string(body))
+	resp, err := c.send(ctx, `POST`, URL, headers, body)
+	zap.S().Debug("Find | HTTP Code: ", resp.StatusCode)
+	if err != nil {
+		return result, err
+	}
+	if err = json.Unmarshal(resp.Body, &result); err != nil {
+		return result, fmt.Errorf("cloudant: unable to decode response: %w", err)
+	}
+	if result.Warning != "" {
+		zap.S().Warn("Find | ", result.Warning)
+	}
+	return result, nil
+}
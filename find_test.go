@@ -0,0 +1,73 @@
+package cloudant
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"testing"
+)
+
+func TestSelector(t *testing.T) {
+	selector := Field("age").Gte(18).And(Or(
+		Field("city").In("Rome", "Milan"),
+		Field("tags").ElemMatch(Field("").Eq("vip")),
+	), Not(Field("name").Regex("^M")))
+	data, _ := json.Marshal(selector)
+	expected := `{"$and":[{"age":{"$gte":18}},{"$or":[{"city":{"$in":["Rome","Milan"]}},{"tags":{"$elemMatch":{"$eq":"vip"}}}]},{"$not":{"name":{"$regex":"^M"}}}]}`
+	if string(data) != expected {
+		t.Errorf("Expected %s, got %s", expected, data)
+	}
+	data, _ = json.Marshal(Field("n").Mod(4, 1).Or(Field("a").Exists(false), Field("s").Size(2)))
+	if expected = `{"$or":[{"n":{"$mod":[4,1]}},{"a":{"$exists":false}},{"s":{"$size":2}}]}`; string(data) != expected {
+		t.Errorf("Expected %s, got %s", expected, data)
+	}
+}
+
+func TestFind(t *testing.T) {
+	srv := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]interface{}
+		body, _ := ioutil.ReadAll(r.Body)
+		if r.Method != "POST" || json.Unmarshal(body, &req) != nil {
+			w.WriteHeader(400)
+			return
+		}
+		switch r.URL.Path {
+		case "/test_db/_find":
+			expected := `{"selector":{"name":{"$eq":"mario"}},"fields":["_id","name"],"sort":[{"name":"desc"}],"limit":10,"use_index":["_design/idx","by_name"],"bookmark":"b1","execution_stats":true}`
+			if string(body) != expected {
+				w.WriteHeader(400)
+				w.Write([]byte(`{"error":"bad_request","reason":"unexpected body"}`))
+				return
+			}
+			w.Write([]byte(`{"docs":[{"_id":"1","name":"mario"}],"bookmark":"b2","execution_stats":{"total_docs_examined":1,"results_returned":1}}`))
+		case "/test_db/_partition/p1/_find":
+			w.Write([]byte(`{"docs":[],"bookmark":"nil","warning":"No matching index found"}`))
+		default:
+			w.WriteHeader(404)
+		}
+	})
+	defer srv.Close()
+	c := newTestClient(srv)
+	result, err := c.Find("test_db", Field("name").Eq("mario"), FindOptions{
+		Fields:         []string{"_id", "name"},
+		Sort:           []Sort{Desc("name")},
+		Limit:          10,
+		UseIndex:       "_design/idx",
+		IndexName:      "by_name",
+		Bookmark:       "b1",
+		ExecutionStats: true,
+	})
+	if err != nil || result.Bookmark != "b2" || result.ExecutionStats == nil || result.ExecutionStats.TotalDocsExamined != 1 {
+		t.Fatal("Unexpected result ", result, err)
+	}
+	var people []testPerson
+	if err = result.Decode(&people); err != nil || len(people) != 1 || people[0].Name != "mario" {
+		t.Error("Unexpected documents ", people, err)
+	}
+	if result, err = c.Find("test_db", nil, FindOptions{Partition: "p1"}); err != nil || result.Warning == "" {
+		t.Error("Expected warning from partitioned query, got ", result, err)
+	}
+	if _, err = c.Find("test_db", nil, FindOptions{}); !IsBadRequest(err) {
+		t.Error("Expected bad request, got ", err)
+	}
+}
@@ -1,13 +1,14 @@
 module github.com/alessiosavi/GoCloudant
 
-go 1.13
+go 1.18
+
+require go.uber.org/zap v1.10.0
 
 require (
-	github.com/alessiosavi/GoUtils v0.0.0-20190925203759-fc1160fa8814
-	github.com/alessiosavi/Requests v0.0.2-0.20190923135050-e7ca66bde738
-	github.com/tidwall/gjson v1.3.2
-	go.uber.org/zap v1.10.0
-	golang.org/x/lint v0.0.0-20190909230951-414d861bb4ac // indirect
-	golang.org/x/sys v0.0.0-20190924154521-2837fb4f24fe // indirect
-	golang.org/x/tools v0.0.0-20190920225731-5eefd052ad72 // indirect
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pkg/errors v0.9.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	github.com/stretchr/testify v1.2.2 // indirect
+	go.uber.org/atomic v1.4.0 // indirect
+	go.uber.org/multierr v1.1.0 // indirect
 )
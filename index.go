@@ -0,0 +1,310 @@
+package cloudant
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// Types of index supported by Cloudant Query
+const (
+	// IndexTypeJSON is the type of the index on the value of the fields
+	IndexTypeJSON = "json"
+	// IndexTypeText is the type of the full text index, based on Lucene
+	IndexTypeText = "text"
+	// indexTypeSpecial is the type of the primary index on `_id`, it can not be modified
+	indexTypeSpecial = "special"
+)
+
+// IndexField is delegated to save a field of an index.
+// Type is the sort order for a JSON index (asc, desc) and the type of the value for a text index (string, number, boolean)
+type IndexField struct {
+	Name string
+	Type string
+}
+
+// MarshalJSON is delegated to encode the field as {"name": "type"}, or as "name" when the type is not set
+func (f IndexField) MarshalJSON() ([]byte, error) {
+	if f.Type == "" {
+		return json.Marshal(f.Name)
+	}
+	return json.Marshal(map[string]string{f.Name: f.Type})
+}
+
+// UnmarshalJSON is delegated to decode the field from {"name": "type"} or "name"
+func (f *IndexField) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &f.Name); err == nil {
+		f.Type = ""
+		return nil
+	}
+	var field map[string]string
+	if err := json.Unmarshal(data, &field); err != nil {
+		return err
+	}
+	if len(field) != 1 {
+		return fmt.Errorf("cloudant: unexpected index field %s", data)
+	}
+	for name, t := range field {
+		f.Name, f.Type = name, t
+	}
+	return nil
+}
+
+// IndexDef is delegated to save the definition of an index
+type IndexDef struct {
+	// Fields indexed
+	Fields []IndexField `json:"fields"`
+	// Only the documents that match the selector are indexed
+	PartialFilterSelector Selector `json:"partial_filter_selector,omitempty"`
+	// Field that is indexed by default in a text index, like {"enabled": true, "analyzer": "standard"}
+	DefaultField interface{} `json:"default_field,omitempty"`
+	// Only the documents that match the selector are indexed in a text index
+	Selector Selector `json:"selector,omitempty"`
+	// Index the length of the arrays in a text index, true if not set
+	IndexArrayLengths *bool `json:"index_array_lengths,omitempty"`
+}
+
+// Index is delegated to save an index of Cloudant Query
+// https://cloud.ibm.com/docs/services/Cloudant?topic=cloudant-query#creating-an-index
+type Index struct {
+	// Design document that contains the index, with or without the `_design/` prefix
+	DDoc string `json:"ddoc,omitempty"`
+	// Name of the index
+	Name string `json:"name"`
+	// Type of the index (IndexTypeJSON, IndexTypeText), IndexTypeJSON if not set
+	Type string `json:"type,omitempty"`
+	// Create the index for a partitioned DB as partitioned (true) or global (false)
+	Partitioned *bool `json:"partitioned,omitempty"`
+	// Definition of the index
+	Def IndexDef `json:"def"`
+}
+
+// indexType return the type of the index, IndexTypeJSON if not set
+func (idx Index) indexType() string {
+	if idx.Type == "" {
+		return IndexTypeJSON
+	}
+	return idx.Type
+}
+
+// ddocName return the name of the design document without the `_design/` prefix
+func (idx Index) ddocName() string {
+	return strings.TrimPrefix(idx.DDoc, `_design/`)
+}
+
+// IndexResult is delegated to save the response of CreateIndex
+type IndexResult struct {
+	// `created` for a new index, `exists` if an index with the same definition alredy exists
+	Result string `json:"result"`
+	// Design document that contains the index
+	ID string `json:"id"`
+	// Name of the index
+	Name string `json:"name"`
+}
+
+// CreateIndex is delegated to create a new index. If an index with the same definition alredy exists nothing is done
+// https://cloud.ibm.com/docs/services/Cloudant?topic=cloudant-query#creating-an-index
+// dbName: DB that we want to index
+// index: design document, name, type and fields of the index
+func (c *Client) CreateIndex(dbName string, index Index) (IndexResult, error) {
+	return c.CreateIndexContext(context.Background(), dbName, index)
+}
+
+// CreateIndexContext is the same as CreateIndex, but the request is bound to the given context
+func (c *Client) CreateIndexContext(ctx context.Context, dbName string, index Index) (IndexResult, error) {
+	var result IndexResult
+	zap.S().Debug("CreateIndex | Creating index [", index.Name, "] on DB [", dbName, "]")
+	if dbName == "" || len(index.Def.Fields) == 0 && index.indexType() == IndexTypeJSON {
+		return result, fmt.Errorf("%w: DB name and fields of the index are mandatory", ErrInvalidArgument)
+	}
+	body, err := json.Marshal(struct {
+		Index       IndexDef `json:"index"`
+		DDoc        string   `json:"ddoc,omitempty"`
+		Name        string   `json:"name,omitempty"`
+		Type        string   `json:"type"`
+		Partitioned *bool    `json:"partitioned,omitempty"`
+	}{index.Def, index.ddocName(), index.Name, index.indexType(), index.Partitioned})
+	if err != nil {
+		return result, fmt.Errorf("%w: unable to encode index: %v", ErrInvalidArgument, err)
+	}
+	URL := c.databaseURL(dbName) + `/_index`
+	headers := newHeader(`Accept`, `application/json`, `Content-Type`, `application/json`)
+	zap.S().Debug("CreateIndex | Sending request to URL: [", URL, "]")
+	resp, err := c.send(ctx, `POST`, URL, headers, body)
+	zap.S().Debug("CreateIndex | HTTP Code: ", resp.StatusCode, " | Body: ", string(resp.Body))
+	if err != nil {
+		return result, err
+	}
+	if err = json.Unmarshal(resp.Body, &result); err != nil {
+		return result, fmt.Errorf("cloudant: unable to decode response: %w", err)
+	}
+	return result, nil
+}
+
+// ListIndexes is delegated to retrieve the indexes of the given DB, including the primary index on `_id`
+// https://cloud.ibm.com/docs/services/Cloudant?topic=cloudant-query#list-all-cloudant-query-indexes
+// dbName: DB that we want to inspect
+func (c *Client) ListIndexes(dbName string) ([]Index, error) {
+	return c.ListIndexesContext(context.Background(), dbName)
+}
+
+// ListIndexesContext is the same as ListIndexes, but the request is bound to the given context
+func (c *Client) ListIndexesContext(ctx context.Context, dbName string) ([]Index, error) {
+	zap.S().Debug("ListIndexes | Retrieving indexes of DB [", dbName, "]")
+	if dbName == "" {
+		return nil, fmt.Errorf("%w: DB name not provided", ErrInvalidArgument)
+	}
+	URL := c.databaseURL(dbName) + `/_index`
+	resp, err := c.send(ctx, `GET`, URL, newHeader(`Accept`, `application/json`), nil)
+	zap.S().Debug("ListIndexes | HTTP Code: ", resp.StatusCode)
+	if err != nil {
+		return nil, err
+	}
+	var result struct {
+		Indexes []Index `json:"indexes"`
+	}
+	if err = json.Unmarshal(resp.Body, &result); err != nil {
+		return nil, fmt.Errorf("cloudant: unable to decode response: %w", err)
+	}
+	return result.Indexes, nil
+}
+
+// DeleteIndex is delegated to delete the given index, identified by design document, type and name
+// https://cloud.ibm.com/docs/services/Cloudant?topic=cloudant-query#deleting-an-index
+// dbName: DB that contains the index
+// index: index to delete, like the ones returned by ListIndexes
+// NOTE: If the index does not exist, IsNotFound will return true for the returned error
+func (c *Client) DeleteIndex(dbName string, index Index) error {
+	return c.DeleteIndexContext(context.Background(), dbName, index)
+}
+
+// DeleteIndexContext is the same as DeleteIndex, but the request is bound to the given context
+func (c *Client) DeleteIndexContext(ctx context.Context, dbName string, index Index) error {
+	zap.S().Debug("DeleteIndex | Deleting index [", index.DDoc, "/", index.Name, "] from DB [", dbName, "]")
+	if dbName == "" || index.ddocName() == "" || index.Name == "" {
+		return fmt.Errorf("%w: DB name, design document and name of the index are mandatory", ErrInvalidArgument)
+	}
+	URL := c.databaseURL(dbName) + `/_index/_design/` + escapeSegment(index.ddocName()) + `/` +
+		escapeSegment(index.indexType()) + `/` + escapeSegment(index.Name)
+	resp, err := c.send(ctx, `DELETE`, URL, newHeader(`Accept`, `application/json`), nil)
+	zap.S().Debug("DeleteIndex | HTTP Code: ", resp.StatusCode)
+	return err
+}
+
+// IndexChanges is delegated to save the changes applied by EnsureIndexes, as design document/name of the indexes
+type IndexChanges struct {
+	Created []string
+	Updated []string
+	Deleted []string
+}
+
+// sameIndex return true if the declared index has the same type and definition of the existing one
+func sameIndex(declared, existing Index) bool {
+	if declared.indexType() != existing.indexType() {
+		return false
+	}
+	normalize := func(def IndexDef) IndexDef {
+		// Cloudant return the JSON fields with the sort order, and can add the default settings of a text index
+		for i := range def.Fields {
+			if def.Fields[i].Type == "" {
+				def.Fields[i].Type = "asc"
+			}
+		}
+		if len(def.PartialFilterSelector) == 0 {
+			def.PartialFilterSelector = nil
+		}
+		if len(def.Selector) == 0 {
+			def.Selector = nil
+		}
+		def.DefaultField, def.IndexArrayLengths = nil, nil
+		return def
+	}
+	a, _ := json.Marshal(normalize(declared.Def))
+	b, _ := json.Marshal(normalize(existing.Def))
+	var x, y interface{}
+	json.Unmarshal(a, &x)
+	json.Unmarshal(b, &y)
+	return reflect.DeepEqual(x, y)
+}
+
+// EnsureIndexes is delegated to make the indexes of the DB match the declared ones, so it can be executed at every
+// start of the application: the missing indexes are created, the ones with a different definition are re-created
+// and the ones not declared are deleted (the primary index is never touched).
+// The indexes are identified by design document and name; when the design document is not set, the name is used
+// dbName: DB that we want to index
+// indexes: declared indexes, the name is mandatory
+func (c *Client) EnsureIndexes(dbName string, indexes []Index) (IndexChanges, error) {
+	return c.EnsureIndexesContext(context.Background(), dbName, indexes)
+}
+
+// EnsureIndexesContext is the same as EnsureIndexes, but the requests are bound to the given context
+func (c *Client) EnsureIndexesContext(ctx context.Context, dbName string, indexes []Index) (IndexChanges, error) {
+	var changes IndexChanges
+	declared := make(map[string]Index, len(indexes))
+	for _, index := range indexes {
+		if index.Name == "" {
+			return changes, fmt.Errorf("%w: name of the index is mandatory", ErrInvalidArgument)
+		}
+		if index.DDoc == "" {
+			index.DDoc = index.Name
+		}
+		declared[index.ddocName()+`/`+index.Name] = index
+	}
+	existing, err := c.ListIndexesContext(ctx, dbName)
+	if err != nil {
+		return changes, err
+	}
+	found := make(map[string]bool, len(existing))
+	for _, index := range existing {
+		if index.Type == indexTypeSpecial {
+			continue
+		}
+		key := index.ddocName() + `/` + index.Name
+		want, ok := declared[key]
+		if ok && sameIndex(want, index) {
+			found[key] = true
+			continue
+		}
+		zap.S().Info("EnsureIndexes | Deleting index [", key, "] from DB [", dbName, "]")
+		if err = c.DeleteIndexContext(ctx, dbName, index); err != nil {
+			return changes, err
+		}
+		if !ok {
+			changes.Deleted = append(changes.Deleted, key)
+		}
+	}
+	// Create the indexes in the declared order, so the result is predictable
+	for _, index := range indexes {
+		if index.DDoc == "" {
+			index.DDoc = index.Name
+		}
+		key := index.ddocName() + `/` + index.Name
+		if found[key] {
+			continue
+		}
+		zap.S().Info("EnsureIndexes | Creating index [", key, "] on DB [", dbName, "]")
+		if _, err = c.CreateIndexContext(ctx, dbName, index); err != nil {
+			return changes, err
+		}
+		if existed := indexExists(existing, key); existed {
+			changes.Updated = append(changes.Updated, key)
+		} else {
+			changes.Created = append(changes.Created, key)
+		}
+	}
+	return changes, nil
+}
+
+// indexExists return true if an index with the given design document/name is in the list
+func indexExists(indexes []Index, key string) bool {
+	for _, index := range indexes {
+		if index.ddocName()+`/`+index.Name == key {
+			return true
+		}
+	}
+	return false
+}
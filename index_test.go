@@ -0,0 +1,104 @@
+package cloudant
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newTestIndexServer is delegated to initialize a fake instance that store the indexes of `test_db` in memory.
+// The JSON fields are returned with the sort order, like Cloudant does
+func newTestIndexServer(indexes *[]Index) *httptest.Server {
+	return newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/test_db/_index":
+			json.NewEncoder(w).Encode(map[string]interface{}{"total_rows": len(*indexes), "indexes": *indexes})
+		case r.Method == "POST" && r.URL.Path == "/test_db/_index":
+			var req struct {
+				Index IndexDef `json:"index"`
+				DDoc  string   `json:"ddoc"`
+				Name  string   `json:"name"`
+				Type  string   `json:"type"`
+			}
+			body, _ := ioutil.ReadAll(r.Body)
+			if json.Unmarshal(body, &req) != nil || req.Type == "" {
+				w.WriteHeader(400)
+				return
+			}
+			for i := range req.Index.Fields {
+				if req.Index.Fields[i].Type == "" {
+					req.Index.Fields[i].Type = "asc"
+				}
+			}
+			*indexes = append(*indexes, Index{DDoc: "_design/" + req.DDoc, Name: req.Name, Type: req.Type, Def: req.Index})
+			json.NewEncoder(w).Encode(IndexResult{Result: "created", ID: "_design/" + req.DDoc, Name: req.Name})
+		case r.Method == "DELETE" && strings.HasPrefix(r.URL.Path, "/test_db/_index/_design/"):
+			parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/test_db/_index/_design/"), "/")
+			for i, index := range *indexes {
+				if index.DDoc == "_design/"+parts[0] && index.Type == parts[1] && index.Name == parts[2] {
+					*indexes = append((*indexes)[:i], (*indexes)[i+1:]...)
+					w.Write([]byte(`{"ok":true}`))
+					return
+				}
+			}
+			w.WriteHeader(404)
+		default:
+			w.WriteHeader(400)
+		}
+	})
+}
+
+func TestIndexes(t *testing.T) {
+	indexes := []Index{{Name: "_all_docs", Type: "special", Def: IndexDef{Fields: []IndexField{{"_id", "asc"}}}}}
+	srv := newTestIndexServer(&indexes)
+	defer srv.Close()
+	c := newTestClient(srv)
+	result, err := c.CreateIndex("test_db", Index{DDoc: "_design/idx", Name: "by_name", Def: IndexDef{Fields: []IndexField{{Name: "name"}}}})
+	if err != nil || result.Result != "created" || result.ID != "_design/idx" {
+		t.Error("Unexpected result ", result, err)
+	}
+	list, err := c.ListIndexes("test_db")
+	if err != nil || len(list) != 2 || list[1].Def.Fields[0] != (IndexField{"name", "asc"}) {
+		t.Fatal("Unexpected indexes ", list, err)
+	}
+	if err = c.DeleteIndex("test_db", list[1]); err != nil || len(indexes) != 1 {
+		t.Error("Expected index deleted, got ", indexes, err)
+	}
+	if err = c.DeleteIndex("test_db", list[1]); !IsNotFound(err) {
+		t.Error("Expected not found, got ", err)
+	}
+}
+
+func TestEnsureIndexes(t *testing.T) {
+	indexes := []Index{
+		{Name: "_all_docs", Type: "special", Def: IndexDef{Fields: []IndexField{{"_id", "asc"}}}},
+		{DDoc: "_design/by_age", Name: "by_age", Type: "json", Def: IndexDef{Fields: []IndexField{{"age", "asc"}}}},
+		{DDoc: "_design/by_city", Name: "by_city", Type: "json", Def: IndexDef{Fields: []IndexField{{"city", "asc"}}}},
+		{DDoc: "_design/old", Name: "old", Type: "json", Def: IndexDef{Fields: []IndexField{{"old", "asc"}}}},
+	}
+	srv := newTestIndexServer(&indexes)
+	defer srv.Close()
+	c := newTestClient(srv)
+	declared := []Index{
+		{Name: "by_age", Def: IndexDef{Fields: []IndexField{{Name: "age"}}}},
+		{Name: "by_city", Def: IndexDef{Fields: []IndexField{{"city", "desc"}}}},
+		{DDoc: "text", Name: "search", Type: IndexTypeText, Def: IndexDef{Fields: []IndexField{{"name", "string"}}}},
+	}
+	changes, err := c.EnsureIndexes("test_db", declared)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(changes.Created, ",") != "text/search" || strings.Join(changes.Updated, ",") != "by_city/by_city" || strings.Join(changes.Deleted, ",") != "old/old" {
+		t.Error("Unexpected changes ", changes)
+	}
+	if len(indexes) != 4 || indexes[0].Type != "special" {
+		t.Error("Unexpected indexes ", indexes)
+	}
+	// Nothing to do the second time
+	if changes, err = c.EnsureIndexes("test_db", declared); err != nil || len(changes.Created)+len(changes.Updated)+len(changes.Deleted) != 0 {
+		t.Error("Expected no changes, got ", changes, err)
+	}
+}
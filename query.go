@@ -0,0 +1,163 @@
+package cloudant
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// maxKeysInURL is the maximum size of the encoded `keys` sent as query parameter, greater ones are sent in a POST body
+const maxKeysInURL = 2048
+
+// Update values accepted by ViewOptions.Update
+const (
+	// UpdateTrue update the index before returning the result (default)
+	UpdateTrue = "true"
+	// UpdateFalse return the result without update the index
+	UpdateFalse = "false"
+	// UpdateLazy return the result without wait the update of the index, that is updated after the request
+	UpdateLazy = "lazy"
+)
+
+// Bool is delegated to return a pointer to the given value, it is used for the optional fields of the options
+func Bool(v bool) *bool {
+	return &v
+}
+
+// ViewOptions is delegated to customize the rows returned by a view (and by `_all_docs`).
+// The keys can be any value that can be encoded as JSON
+// https://cloud.ibm.com/docs/services/Cloudant?topic=cloudant-using-views#using-views
+type ViewOptions struct {
+	// Return only the rows that match the given key
+	Key interface{}
+	// Return only the rows that match the given keys, in the same order
+	Keys []interface{}
+	// Return only the rows with a key greater or equal than the given one
+	StartKey interface{}
+	// `_id` of the first row, when more rows have the same StartKey
+	StartKeyDocID string
+	// Return only the rows with a key lower or equal than the given one
+	EndKey interface{}
+	// `_id` of the last row, when more rows have the same EndKey
+	EndKeyDocID string
+	// Include the rows with key equal to EndKey, true if not set
+	InclusiveEnd *bool
+	// Maximum number of rows returned, 0 for no limit
+	Limit int
+	// Number of rows to skip
+	Skip int
+	// Return the rows in reverse order, StartKey have to be greater than EndKey
+	Descending bool
+	// Return the content of the documents in the rows
+	IncludeDocs bool
+	// Return the conflicted revisions of the documents, only with IncludeDocs
+	Conflicts bool
+	// Whether the index is updated before returning the result (UpdateTrue, UpdateFalse, UpdateLazy)
+	Update string
+	// Use the same set of shards for every request, the result can be less updated
+	Stable bool
+	// Use the reduce function of the view, true if not set and the view has one
+	Reduce *bool
+	// Group the results by key, only with reduce
+	Group bool
+	// Group the results by the first N elements of an array key, only with reduce
+	GroupLevel int
+	// Query only the given partition of a partitioned DB, the view have to be partitioned
+	Partition string
+}
+
+// encode is delegated to encode the options as query parameters.
+// When the encoded `keys` are too long for the URL, they are returned as body of a POST request
+func (opts ViewOptions) encode() (url.Values, []byte, error) {
+	q := url.Values{}
+	setBool := func(name string, value bool) {
+		if value {
+			q.Set(name, "true")
+		}
+	}
+	setInt := func(name string, value int) {
+		if value > 0 {
+			q.Set(name, strconv.Itoa(value))
+		}
+	}
+	setBool("descending", opts.Descending)
+	setBool("include_docs", opts.IncludeDocs)
+	setBool("conflicts", opts.Conflicts)
+	setBool("stable", opts.Stable)
+	setBool("group", opts.Group)
+	setInt("limit", opts.Limit)
+	setInt("skip", opts.Skip)
+	setInt("group_level", opts.GroupLevel)
+	if opts.InclusiveEnd != nil {
+		q.Set("inclusive_end", strconv.FormatBool(*opts.InclusiveEnd))
+	}
+	if opts.Reduce != nil {
+		q.Set("reduce", strconv.FormatBool(*opts.Reduce))
+	}
+	if opts.Update != "" {
+		q.Set("update", opts.Update)
+	}
+	if opts.StartKeyDocID != "" {
+		q.Set("startkey_docid", opts.StartKeyDocID)
+	}
+	if opts.EndKeyDocID != "" {
+		q.Set("endkey_docid", opts.EndKeyDocID)
+	}
+	keys := []struct {
+		name  string
+		value interface{}
+	}{{"key", opts.Key}, {"startkey", opts.StartKey}, {"endkey", opts.EndKey}}
+	for _, key := range keys {
+		if key.value == nil {
+			continue
+		}
+		encoded, err := encodeKey(key.value)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: unable to encode %s: %v", ErrInvalidArgument, key.name, err)
+		}
+		q.Set(key.name, string(encoded))
+	}
+	if opts.Keys == nil {
+		return q, nil, nil
+	}
+	encoded, err := encodeKey(opts.Keys)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: unable to encode keys: %v", ErrInvalidArgument, err)
+	}
+	if len(encoded) <= maxKeysInURL {
+		q.Set("keys", string(encoded))
+		return q, nil, nil
+	}
+	body, _ := json.Marshal(struct {
+		Keys json.RawMessage `json:"keys"`
+	}{encoded})
+	return q, body, nil
+}
+
+// encodeKey is delegated to encode the given key as JSON, without escaping the HTML characters
+func encodeKey(key interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(key); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// queryRequest is delegated to compose the method, the URL and the body of a request for the given options
+func queryRequest(URL string, opts ViewOptions) (string, string, []byte, error) {
+	q, body, err := opts.encode()
+	if err != nil {
+		return "", "", nil, err
+	}
+	if len(q) > 0 {
+		URL += `?` + q.Encode()
+	}
+	if body != nil {
+		return `POST`, URL, body, nil
+	}
+	return `GET`, URL, nil, nil
+}
@@ -0,0 +1,102 @@
+package cloudant
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestViewOptionsEncode(t *testing.T) {
+	opts := ViewOptions{
+		StartKey:     []interface{}{"a&b", 1},
+		EndKey:       `"quoted"`,
+		InclusiveEnd: Bool(false),
+		Reduce:       Bool(false),
+		Limit:        10,
+		Skip:         5,
+		IncludeDocs:  true,
+		Update:       UpdateLazy,
+		Keys:         []interface{}{"x", 2},
+	}
+	q, body, err := opts.encode()
+	if err != nil || body != nil {
+		t.Fatal("Unexpected result ", body, err)
+	}
+	expected := map[string]string{
+		"startkey":      `["a&b",1]`,
+		"endkey":        `"\"quoted\""`,
+		"inclusive_end": "false",
+		"reduce":        "false",
+		"limit":         "10",
+		"skip":          "5",
+		"include_docs":  "true",
+		"update":        "lazy",
+		"keys":          `["x",2]`,
+	}
+	for name, value := range expected {
+		if q.Get(name) != value {
+			t.Errorf("Expected %s=%s, got %s", name, value, q.Get(name))
+		}
+	}
+	if len(q) != len(expected) {
+		t.Error("Unexpected parameters ", q)
+	}
+	// The encoded parameters are escaped
+	if parsed, _ := url.ParseQuery(q.Encode()); parsed.Get("startkey") != expected["startkey"] {
+		t.Error("Unexpected escaping ", q.Encode())
+	}
+	if _, _, err = (ViewOptions{Key: func() {}}).encode(); err == nil {
+		t.Error("Expected error for key that can not be encoded")
+	}
+}
+
+func TestViewOptionsLargeKeys(t *testing.T) {
+	keys := make([]interface{}, 500)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("key-%d", i)
+	}
+	method, URL, body, err := queryRequest("http://localhost/db/_all_docs", ViewOptions{Keys: keys, Limit: 1})
+	if err != nil || method != "POST" || strings.Contains(URL, "keys") || !strings.HasSuffix(URL, "?limit=1") {
+		t.Fatal("Expected keys sent in the body, got ", method, URL, err)
+	}
+	var decoded struct {
+		Keys []string `json:"keys"`
+	}
+	if json.Unmarshal(body, &decoded) != nil || len(decoded.Keys) != 500 {
+		t.Error("Unexpected body ", string(body))
+	}
+}
+
+func TestGetView(t *testing.T) {
+	srv := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/test_db/_design/users/_view/by_name" {
+			w.WriteHeader(404)
+			return
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		if r.Method == "POST" && !strings.HasPrefix(string(body), `{"keys":["`) {
+			w.WriteHeader(400)
+			return
+		}
+		fmt.Fprintf(w, `{"total_rows":1,"offset":0,"rows":[{"id":"1","key":%s,"value":null}]}`, r.URL.Query().Get("key"))
+	})
+	defer srv.Close()
+	c := newTestClient(srv)
+	if rows, err := c.GetView("test_db", "_design/users", "by_name", ViewOptions{Key: "mario"}); err != nil || !strings.Contains(rows, `"key":"mario"`) {
+		t.Error("Unexpected rows ", rows, err)
+	}
+	keys := make([]interface{}, 500)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("key-%d", i)
+	}
+	if _, err := c.GetView("test_db", "users", "by_name", ViewOptions{Keys: keys}); err != nil {
+		t.Error(err)
+	}
+	if _, err := c.GetView("test_db", "users", "missing", ViewOptions{}); !IsNotFound(err) {
+		t.Error("Expected not found, got ", err)
+	}
+}
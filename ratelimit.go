@@ -0,0 +1,184 @@
+package cloudant
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// RequestClass is the class used by Cloudant for count the requests against the provisioned throughput
+// https://cloud.ibm.com/docs/Cloudant?topic=Cloudant-ibm-cloud-publicprovisioned-throughput-capacity
+type RequestClass int
+
+const (
+	// ReadRequest is a lookup of a document by `_id`, or a query against a partitioned index (including `_all_docs`)
+	ReadRequest RequestClass = iota
+	// WriteRequest is the creation, modification or deletion of a document
+	WriteRequest
+	// QueryRequest is a request to a global index: Cloudant Query (_find), views, search and `_all_docs`
+	QueryRequest
+)
+
+// String is delegated to return the name of the class
+func (class RequestClass) String() string {
+	switch class {
+	case ReadRequest:
+		return "read"
+	case WriteRequest:
+		return "write"
+	case QueryRequest:
+		return "query"
+	}
+	return "unknown"
+}
+
+// Throughput is delegated to save the capacity of the instance, as number of operations per second for each class.
+// A value lower than 1 means that the related class is not limited
+type Throughput struct {
+	Reads   int
+	Writes  int
+	Queries int
+}
+
+// LitePlanThroughput is the capacity of the Cloudant Lite plan
+var LitePlanThroughput = Throughput{Reads: 20, Writes: 10, Queries: 5}
+
+// RateLimiter is delegated to delay the requests for not exceed the throughput of the Cloudant instance.
+// Every class of requests is limited by a token bucket that can be filled up to the capacity of one second.
+// The same RateLimiter can be shared by several clients and it is safe for concurrent use
+type RateLimiter struct {
+	buckets map[RequestClass]*tokenBucket
+}
+
+// NewRateLimiter is delegated to initialize a new RateLimiter for the given throughput
+func NewRateLimiter(capacity Throughput) *RateLimiter {
+	return &RateLimiter{buckets: map[RequestClass]*tokenBucket{
+		ReadRequest:  newTokenBucket(capacity.Reads),
+		WriteRequest: newTokenBucket(capacity.Writes),
+		QueryRequest: newTokenBucket(capacity.Queries),
+	}}
+}
+
+// WithRateLimiter is delegated to set the RateLimiter used for throttle every request sent by the client.
+// When not provided, the requests are never delayed
+func WithRateLimiter(limiter *RateLimiter) Option {
+	return func(c *Client) {
+		c.limiter = limiter
+	}
+}
+
+// Wait is delegated to block until `n` operations of the given class can be executed.
+// An error is returned if the context is cancelled before, in that case the operations are not consumed
+func (l *RateLimiter) Wait(ctx context.Context, class RequestClass, n int) error {
+	bucket := l.buckets[class]
+	if bucket == nil {
+		return nil
+	}
+	return bucket.wait(ctx, n)
+}
+
+// tokenBucket is delegated to limit the operations to `rate` per second
+type tokenBucket struct {
+	rate float64
+	// now is used for retrieve the current time, overridden during the test
+	now func() time.Time
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// newTokenBucket is delegated to initialize a full bucket for the given rate. Nil is returned if rate is lower than 1
+func newTokenBucket(rate int) *tokenBucket {
+	if rate < 1 {
+		return nil
+	}
+	return &tokenBucket{rate: float64(rate), tokens: float64(rate), now: time.Now}
+}
+
+// wait is delegated to reserve `n` tokens, waiting until they are available
+func (b *tokenBucket) wait(ctx context.Context, n int) error {
+	b.mu.Lock()
+	now := b.now()
+	if !b.last.IsZero() {
+		b.tokens += now.Sub(b.last).Seconds() * b.rate
+		if b.tokens > b.rate {
+			b.tokens = b.rate
+		}
+	}
+	b.last = now
+	b.tokens -= float64(n)
+	delay := time.Duration(-b.tokens / b.rate * float64(time.Second))
+	b.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+	zap.S().Debug("wait | Throughput exceeded, waiting ", delay)
+	if err := sleep(ctx, delay); err != nil {
+		// Give back the tokens that will not be used
+		b.mu.Lock()
+		b.tokens += float64(n)
+		b.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// classifyRequest is delegated to compute the class of the request and the number of operations that it consume,
+// following the rules used by Cloudant
+// path: path of the request, relative to the URL of the instance
+func classifyRequest(method, path string, body []byte) (RequestClass, int) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	partitioned := len(segments) > 1 && segments[1] == "_partition"
+	for _, segment := range segments {
+		switch segment {
+		case "_find", "_view", "_search", "_explain", "_all_docs":
+			if partitioned {
+				return ReadRequest, 1
+			}
+			return QueryRequest, 1
+		case "_bulk_docs":
+			return WriteRequest, countDocs(body)
+		case "_bulk_get":
+			return ReadRequest, countDocs(body)
+		case "_changes":
+			return ReadRequest, 1
+		}
+	}
+	if method == http.MethodGet || method == http.MethodHead {
+		return ReadRequest, 1
+	}
+	return WriteRequest, 1
+}
+
+// countDocs is delegated to count the documents in the body of a bulk request, at least 1 is returned
+func countDocs(body []byte) int {
+	var bulk struct {
+		Docs []json.RawMessage `json:"docs"`
+	}
+	if json.Unmarshal(body, &bulk) != nil || len(bulk.Docs) == 0 {
+		return 1
+	}
+	return len(bulk.Docs)
+}
+
+// throttle is delegated to wait until the request can be sent without exceed the throughput of the instance
+func (c *Client) throttle(ctx context.Context, method, URL string, body []byte) error {
+	if c.limiter == nil {
+		return nil
+	}
+	// The escaped path is used, for not split the names that contain a slash (ex: DB `a/b`)
+	path := strings.TrimPrefix(URL, c.dbURL)
+	if u, err := url.Parse(path); err == nil {
+		path = u.EscapedPath()
+	}
+	class, n := classifyRequest(method, path, body)
+	return c.limiter.Wait(ctx, class, n)
+}
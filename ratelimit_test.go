@@ -0,0 +1,124 @@
+package cloudant
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestClassifyRequest(t *testing.T) {
+	cases := []struct {
+		method string
+		path   string
+		body   string
+		class  RequestClass
+		n      int
+	}{
+		{"GET", "/db/doc", "", ReadRequest, 1},
+		{"HEAD", "/db/doc", "", ReadRequest, 1},
+		{"PUT", "/db/doc", "{}", WriteRequest, 1},
+		{"DELETE", "/db/doc", "", WriteRequest, 1},
+		{"POST", "/db", "{}", WriteRequest, 1},
+		{"POST", "/db/_bulk_docs", `{"docs":[{},{},{}]}`, WriteRequest, 3},
+		{"POST", "/db/_bulk_get", `{"docs":[{"id":"a"},{"id":"b"}]}`, ReadRequest, 2},
+		{"GET", "/db/_all_docs", "", QueryRequest, 1},
+		{"POST", "/db/_all_docs/queries", `{"queries":[{},{}]}`, QueryRequest, 1},
+		{"GET", "/db/_partition/p1/_all_docs", "", ReadRequest, 1},
+		{"GET", "/db/_changes", "", ReadRequest, 1},
+		{"POST", "/db/_find", "{}", QueryRequest, 1},
+		{"GET", "/db/_design/ddoc/_view/by_name", "", QueryRequest, 1},
+		{"GET", "/db/_design/ddoc/_search/idx", "", QueryRequest, 1},
+		{"POST", "/db/_partition/p1/_find", "{}", ReadRequest, 1},
+		{"GET", "/db/_partition/p1/_design/ddoc/_view/by_name", "", ReadRequest, 1},
+	}
+	for _, c := range cases {
+		class, n := classifyRequest(c.method, c.path, []byte(c.body))
+		if class != c.class || n != c.n {
+			t.Error(c.method, " ", c.path, " expected ", c.class, "/", c.n, " got ", class, "/", n)
+		}
+	}
+}
+
+func TestRateLimiter(t *testing.T) {
+	limiter := NewRateLimiter(Throughput{Reads: 10})
+	// Freeze the time, so the bucket is never refilled
+	now := time.Now()
+	limiter.buckets[ReadRequest].now = func() time.Time { return now }
+
+	start := time.Now()
+	if err := limiter.Wait(context.Background(), ReadRequest, 10); err != nil {
+		t.Error(err)
+	}
+	if time.Since(start) > 50*time.Millisecond {
+		t.Error("The capacity of one second should be available without waiting")
+	}
+	// Writes are not limited
+	if err := limiter.Wait(context.Background(), WriteRequest, 1000); err != nil {
+		t.Error(err)
+	}
+	start = time.Now()
+	if err := limiter.Wait(context.Background(), ReadRequest, 1); err != nil {
+		t.Error(err)
+	}
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Error("Expected to wait for 100ms, waited ", elapsed)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := limiter.Wait(ctx, ReadRequest, 100); !errors.Is(err, context.DeadlineExceeded) {
+		t.Error("Expected deadline exceeded, got ", err)
+	}
+	// The tokens reserved by the cancelled request are given back
+	if tokens := limiter.buckets[ReadRequest].tokens; tokens != -1 {
+		t.Error("Unexpected tokens ", tokens)
+	}
+}
+
+func TestThrottleEscapedPath(t *testing.T) {
+	srv := newTestServer(func(w http.ResponseWriter, r *http.Request) {})
+	defer srv.Close()
+	c := newTestClient(srv)
+	limiter := NewRateLimiter(Throughput{Reads: 1, Queries: 1})
+	now := time.Now()
+	limiter.buckets[ReadRequest].now = func() time.Time { return now }
+	limiter.buckets[QueryRequest].now = func() time.Time { return now }
+	WithRateLimiter(limiter)(c)
+	// No more queries are available
+	if err := limiter.Wait(context.Background(), QueryRequest, 1); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	// A document of the DB `test/_find`, not a query
+	if err := c.throttle(ctx, "GET", c.docURL("test/_find", "1"), nil); err != nil {
+		t.Error("Expected request counted as read, got ", err)
+	}
+}
+
+func TestClientRateLimiter(t *testing.T) {
+	srv := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"_id":"1","_rev":"1-abc"}`))
+	})
+	defer srv.Close()
+	c := newTestClient(srv)
+	WithRateLimiter(NewRateLimiter(Throughput{Reads: 20}))(c)
+	var wg sync.WaitGroup
+	start := time.Now()
+	// 20 reads are available immediately, the other 10 require half second
+	for i := 0; i < 30; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := c.GetDocument("test_db", "1"); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	if elapsed := time.Since(start); elapsed < 400*time.Millisecond {
+		t.Error("Expected the requests to be throttled, elapsed ", elapsed)
+	}
+}
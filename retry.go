@@ -0,0 +1,162 @@
+package cloudant
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// RetryPolicy is delegated to configure the retry of the requests rejected due to throttling (429) or to a
+// temporary error of the server (5xx).
+// The requests rejected with 429 are always retried, since Cloudant does not process them; the requests that fail
+// with 5xx or with a network error are retried only if they are idempotent (GET, HEAD, PUT, DELETE) or if they
+// are a POST to a read only endpoint (_all_docs, _find, _view ...)
+type RetryPolicy struct {
+	// Maximum number of attempts, including the first one. A value lower than 2 disable the retry
+	MaxAttempts int
+	// Delay before the first retry, doubled at every attempt
+	BaseDelay time.Duration
+	// Maximum delay between two attempts, not applied to the delay requested by the server with Retry-After
+	MaxDelay time.Duration
+	// OnRetry, if not nil, is called before waiting for the next attempt
+	OnRetry func(RetryEvent)
+}
+
+// RetryEvent is delegated to save the information related to a request that is going to be retried
+type RetryEvent struct {
+	// HTTP method of the request
+	Method string
+	// URL of the request
+	URL string
+	// Number of the failed attempt, starting from 1
+	Attempt int
+	// HTTP status code of the failed attempt, 0 for a network error
+	StatusCode int
+	// Error returned by the failed attempt
+	Err error
+	// Time that will be waited before the next attempt
+	Delay time.Duration
+}
+
+// DefaultRetryPolicy is delegated to return a retry policy suitable for the Cloudant Lite and Standard plan:
+// 5 attempts, starting from 250ms up to 10s between the attempts
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{MaxAttempts: 5, BaseDelay: 250 * time.Millisecond, MaxDelay: 10 * time.Second}
+}
+
+// WithRetryPolicy is delegated to set the retry policy used for every request.
+// When not provided, the requests are never retried
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = policy
+	}
+}
+
+// safePostEndpoints contains the endpoints that accept a POST without modifying the data
+var safePostEndpoints = map[string]bool{
+	"_all_docs": true, "_find": true, "_explain": true, "_bulk_get": true, "_changes": true, "_search_analyze": true, "queries": true,
+}
+
+// safePostIndexes contains the kinds of index queried with POST /{db}/_design/{ddoc}/{kind}/{name}
+var safePostIndexes = map[string]bool{
+	"_view": true, "_search": true, "_search_analyze": true,
+}
+
+// isIdempotent is delegated to verify if the given request can be sent more than once without side effects
+func isIdempotent(method, URL string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete, http.MethodOptions:
+		return true
+	case http.MethodPost:
+		u, err := url.Parse(URL)
+		if err != nil {
+			return false
+		}
+		// Views and search indexes are queried with POST /{db}/_design/{ddoc}/{kind}/{name}
+		dir, endpoint := path.Split(u.EscapedPath())
+		return safePostEndpoints[endpoint] || safePostIndexes[path.Base(dir)]
+	}
+	return false
+}
+
+// retryDelay is delegated to verify if the failed attempt have to be retried, and to compute the time to wait
+// before the next one
+func (policy RetryPolicy) retryDelay(attempt int, method, URL string, resp response, err error) (time.Duration, bool) {
+	if err == nil || attempt >= policy.MaxAttempts {
+		return 0, false
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) || errors.Is(err, ErrInvalidArgument) {
+		return 0, false
+	}
+	status := StatusCode(err)
+	switch {
+	case status == http.StatusTooManyRequests:
+	case status >= 500 || (status == 0 && resp.StatusCode == 0):
+		// Server or network error, the request could have been processed
+		if !isIdempotent(method, URL) {
+			return 0, false
+		}
+	default:
+		return 0, false
+	}
+	if delay, ok := parseRetryAfter(resp.Header); ok {
+		return delay, true
+	}
+	delay := policy.BaseDelay << uint(attempt-1)
+	if delay <= 0 || (policy.MaxDelay > 0 && delay > policy.MaxDelay) {
+		delay = policy.MaxDelay
+	}
+	if delay > 0 {
+		// Equal jitter in [delay/2, delay], for avoid that every client retry at the same time
+		delay = delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+	}
+	return delay, true
+}
+
+// parseRetryAfter is delegated to extract the delay requested by the server with the Retry-After header,
+// expressed in seconds or as HTTP date
+func parseRetryAfter(header http.Header) (time.Duration, bool) {
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		delay := time.Until(date)
+		if delay < 0 {
+			delay = 0
+		}
+		return delay, true
+	}
+	return 0, false
+}
+
+// sleep is delegated to wait for the given time, returning earlier if the context is cancelled
+func sleep(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// notifyRetry is delegated to log the retry and to call the hook of the policy
+func (policy RetryPolicy) notifyRetry(event RetryEvent) {
+	zap.S().Warn("retry | ", event.Method, " ", event.URL, " | Attempt ", event.Attempt, " failed with status ",
+		event.StatusCode, " | Retrying in ", event.Delay, " | Err: ", event.Err)
+	if policy.OnRetry != nil {
+		policy.OnRetry(event)
+	}
+}
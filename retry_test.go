@@ -0,0 +1,145 @@
+package cloudant
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTestRetryClient is delegated to initialize a client connected to a fake Cloudant instance that fail
+// the first `failures` requests with the given status code
+func newTestRetryClient(failures int32, status int, events *[]RetryEvent) (*Client, func(), *int32) {
+	var requests int32
+	srv := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) <= failures {
+			if status == 429 {
+				w.Header().Set("Retry-After", "0")
+			}
+			w.WriteHeader(status)
+			w.Write([]byte(`{"error":"too_many_requests","reason":"You've exceeded your current limit"}`))
+			return
+		}
+		w.WriteHeader(201)
+		w.Write([]byte(`{"ok":true}`))
+	})
+	c := newTestClient(srv)
+	policy := DefaultRetryPolicy()
+	policy.MaxAttempts = 3
+	policy.OnRetry = func(event RetryEvent) { *events = append(*events, event) }
+	WithRetryPolicy(policy)(c)
+	return c, srv.Close, &requests
+}
+
+func TestRetryRateLimited(t *testing.T) {
+	var events []RetryEvent
+	c, stop, requests := newTestRetryClient(2, 429, &events)
+	defer stop()
+	// Even a non idempotent request is retried after a 429
+	if _, err := c.InsertDocument("test_db", []byte(`{}`)); err != nil {
+		t.Error(err)
+	}
+	if atomic.LoadInt32(requests) != 3 || len(events) != 2 {
+		t.Error("Expected 2 retry, got ", len(events))
+	}
+	if events[0].Attempt != 1 || events[0].StatusCode != 429 || !IsRateLimited(events[0].Err) {
+		t.Error("Unexpected event ", events[0])
+	}
+}
+
+func TestRetryExhausted(t *testing.T) {
+	var events []RetryEvent
+	c, stop, requests := newTestRetryClient(5, 429, &events)
+	defer stop()
+	if err := c.CreateDB("test_db", false); !IsRateLimited(err) {
+		t.Error("Expected rate limited, got ", err)
+	}
+	if atomic.LoadInt32(requests) != 3 {
+		t.Error("Expected 3 attempts, got ", *requests)
+	}
+}
+
+func TestRetryServerError(t *testing.T) {
+	var events []RetryEvent
+	c, stop, requests := newTestRetryClient(2, 503, &events)
+	defer stop()
+	// POST to _bulk_docs is not idempotent, it is not retried
+	if _, err := c.InsertBulkDocument("test_db", []string{`{}`}); StatusCode(err) != 503 {
+		t.Error("Expected service unavailable, got ", err)
+	}
+	// PUT is idempotent
+	if err := c.CreateDB("test_db", false); err != nil {
+		t.Error(err)
+	}
+	if atomic.LoadInt32(requests) != 3 || len(events) != 1 {
+		t.Error("Expected 1 retry, got ", len(events))
+	}
+}
+
+func TestRetryContext(t *testing.T) {
+	var events []RetryEvent
+	c, stop, _ := newTestRetryClient(5, 503, &events)
+	defer stop()
+	c.retry.BaseDelay = time.Hour
+	c.retry.MaxDelay = time.Hour
+	// Long enough for the first attempt also on a slow machine, the retry wait an hour
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	if err := c.RemoveDBContext(ctx, "test_db"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Error("Expected deadline exceeded while waiting, got ", err)
+	}
+	if len(events) != 1 {
+		t.Error("Expected 1 retry, got ", len(events))
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 10, BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+	err := &CloudantError{StatusCode: 429}
+	for attempt, max := range []time.Duration{100, 200, 400, 800, 1000, 1000} {
+		delay, ok := policy.retryDelay(attempt+1, "GET", "https://host/db", response{StatusCode: 429}, err)
+		max *= time.Millisecond
+		if !ok || delay < max/2 || delay > max {
+			t.Error("Attempt ", attempt+1, " unexpected delay ", delay)
+		}
+	}
+	resp := response{StatusCode: 429, Header: http.Header{"Retry-After": []string{"7"}}}
+	if delay, ok := policy.retryDelay(1, "GET", "https://host/db", resp, err); !ok || delay != 7*time.Second {
+		t.Error("Expected delay from Retry-After, got ", delay)
+	}
+	if _, ok := policy.retryDelay(1, "GET", "https://host/db", response{StatusCode: 404}, &CloudantError{StatusCode: 404}); ok {
+		t.Error("Not found should not be retried")
+	}
+	if _, ok := policy.retryDelay(1, "POST", "https://host/db", response{}, errors.New("connection reset")); ok {
+		t.Error("Network error on POST should not be retried")
+	}
+	if _, ok := policy.retryDelay(1, "POST", "https://host/db/_find", response{}, errors.New("connection reset")); !ok {
+		t.Error("Network error on _find should be retried")
+	}
+}
+
+func TestIsIdempotent(t *testing.T) {
+	cases := map[string]bool{
+		"GET https://host/db/doc":                                    true,
+		"PUT https://host/db/doc":                                    true,
+		"POST https://host/db":                                       false,
+		"POST https://host/db/_bulk_docs":                            false,
+		"POST https://host/db/_all_docs":                             true,
+		"POST https://host/db/_design/ddoc/_view/by_age":             true,
+		"POST https://host/db/_design/ddoc/_update/fn":               false,
+		"POST https://host/db/_design/ddoc/_search/idx":              true,
+		"POST https://host/db/_partition/p/_design/ddoc/_search/idx": true,
+		"POST https://host/db/_design/ddoc/_search_analyze/idx":      true,
+		"POST https://host/_search_analyze":                          true,
+		"POST https://host/db/_design/ddoc/_update/_view%2Ffn":       false,
+	}
+	for request, expected := range cases {
+		parts := strings.SplitN(request, " ", 2)
+		if isIdempotent(parts[0], parts[1]) != expected {
+			t.Error("Unexpected result for ", request)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package cloudant
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"go.uber.org/zap"
+)
+
+// SearchOptions is delegated to customize the query executed by Search
+// https://cloud.ibm.com/docs/services/Cloudant?topic=cloudant-cloudant-search#queries
+type SearchOptions struct {
+	// Lucene query (ex: name:mario AND age:[18 TO 30])
+	Query string `json:"query"`
+	// Sort order of the results, like "-price<number>" or "name<string>"
+	Sort []string `json:"sort,omitempty"`
+	// Bookmark returned by a previous search, for request the next page of results
+	Bookmark string `json:"bookmark,omitempty"`
+	// Maximum number of results, 25 (the Cloudant default) if not set
+	Limit int `json:"limit,omitempty"`
+	// Return the content of the documents in the results
+	IncludeDocs bool `json:"include_docs,omitempty"`
+	// Stored fields returned for every result, every stored field if empty
+	IncludeFields []string `json:"include_fields,omitempty"`
+	// Fields for which the number of results of every value is counted (facets)
+	Counts []string `json:"counts,omitempty"`
+	// Numeric ranges for which the number of results is counted, like {"price": {"cheap": "[0 TO 100]"}}
+	Ranges map[string]map[string]string `json:"ranges,omitempty"`
+	// Restrict the results to the given field/value pairs, like [["city", "Rome"], ["city", "Milan"]]
+	Drilldown [][]string `json:"drilldown,omitempty"`
+	// Group the results by the given field
+	GroupField string `json:"group_field,omitempty"`
+	// Maximum number of results for every group
+	GroupLimit int `json:"group_limit,omitempty"`
+	// Sort order of the results inside every group
+	GroupSort []string `json:"group_sort,omitempty"`
+	// Fields for which the matching terms are highlighted
+	HighlightFields []string `json:"highlight_fields,omitempty"`
+	// Tag inserted before every highlighted term, <em> if not set
+	HighlightPreTag string `json:"highlight_pre_tag,omitempty"`
+	// Tag inserted after every highlighted term, </em> if not set
+	HighlightPostTag string `json:"highlight_post_tag,omitempty"`
+	// Number of fragments returned for every highlighted field
+	HighlightNumber int `json:"highlight_number,omitempty"`
+	// Number of characters of every fragment
+	HighlightSize int `json:"highlight_size,omitempty"`
+	// Search only the given partition of a partitioned DB, the index have to be partitioned
+	Partition string `json:"-"`
+}
+
+// SearchRow is delegated to save a result of a search
+type SearchRow struct {
+	// `_id` of the document
+	ID string `json:"id"`
+	// Sort values of the result, used for the paging
+	Order []interface{} `json:"order"`
+	// Stored fields of the document
+	Fields map[string]interface{} `json:"fields"`
+	// Content of the document, only with IncludeDocs
+	Doc json.RawMessage `json:"doc,omitempty"`
+	// Fragments with the highlighted terms, indexed by field
+	Highlights map[string][]string `json:"highlights,omitempty"`
+}
+
+// DecodeDoc is delegated to decode the content of the document into v, only with IncludeDocs
+func (r SearchRow) DecodeDoc(v interface{}) error {
+	if len(r.Doc) == 0 {
+		return fmt.Errorf("cloudant: document %s not included in the result", r.ID)
+	}
+	return json.Unmarshal(r.Doc, v)
+}
+
+// SearchGroup is delegated to save the results related to a value of the GroupField
+type SearchGroup struct {
+	// Value of the GroupField
+	By string `json:"by"`
+	// Number of results of the group
+	TotalRows int `json:"total_rows"`
+	// Results of the group
+	Rows []SearchRow `json:"rows"`
+}
+
+// SearchResult is delegated to save the response of a search
+type SearchResult struct {
+	// Number of results that match the query
+	TotalRows int `json:"total_rows"`
+	// Bookmark to use for request the next page of results
+	Bookmark string `json:"bookmark,omitempty"`
+	// Results of the page, empty when GroupField is used
+	Rows []SearchRow `json:"rows"`
+	// Number of results for every value of the Counts fields, indexed by field and value
+	Counts map[string]map[string]int `json:"counts,omitempty"`
+	// Number of results for every range of the Ranges fields, indexed by field and range name
+	Ranges map[string]map[string]int `json:"ranges,omitempty"`
+	// Results grouped by GroupField
+	Groups []SearchGroup `json:"groups,omitempty"`
+}
+
+// Search is delegated to query the given search index
+// https://cloud.ibm.com/docs/services/Cloudant?topic=cloudant-cloudant-search#queries
+// dbName: DB that contains the design document
+// ddoc: name of the design document, with or without the `_design/` prefix
+// index: name of the search index
+// opts: query, sort, facets and highlights of the results
+func (c *Client) Search(dbName, ddoc, index string, opts SearchOptions) (SearchResult, error) {
+	return c.SearchContext(context.Background(), dbName, ddoc, index, opts)
+}
+
+// SearchContext is the same as Search, but the request is bound to the given context
+func (c *Client) SearchContext(ctx context.Context, dbName, ddoc, index string, opts SearchOptions) (SearchResult, error) {
+	var result SearchResult
+	zap.S().Debug("Search | Searching [", opts.Query, "] in [", ddoc, "/", index, "] of DB [", dbName, "] ...")
+	resp, err := c.sendSearch(ctx, dbName, ddoc, index, opts)
+	if err != nil {
+		return result, err
+	}
+	defer resp.Body.Close()
+	if err = json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return result, fmt.Errorf("cloudant: unable to decode response: %w", err)
+	}
+	return result, nil
+}
+
+// sendSearch is delegated to send the search request, the body of the response have to be closed by the caller
+func (c *Client) sendSearch(ctx context.Context, dbName, ddoc, index string, opts SearchOptions) (*http.Response, error) {
+	if dbName == "" || ddoc == "" || index == "" || opts.Query == "" {
+		return nil, fmt.Errorf("%w: DB name, design document, index and query are mandatory", ErrInvalidArgument)
+	}
+	body, err := json.Marshal(opts)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unable to encode search: %v", ErrInvalidArgument, err)
+	}
+	URL := c.designURL(dbName, opts.Partition, ddoc, `_search`, index)
+	headers := newHeader(`Accept`, `application/json`, `Content-Type`, `application/json`)
+	zap.S().Debug("Search | Sending request to URL: [", URL, "] | Query: ", string(body))
+	return c.stream(ctx, `POST`, URL, headers, body)
+}
+
+// SearchIterator is delegated to iterate over all the results of the given search, following the bookmarks.
+// Every result can be decoded as SearchRow. The Limit of the options is used as page size (200 if not set),
+// and the Bookmark as starting point. Counts, Ranges and GroupField are not supported
+// dbName: DB that contains the design document
+// ddoc: name of the design document, with or without the `_design/` prefix
+// index: name of the search index
+// opts: query and sort of the results
+func (c *Client) SearchIterator(dbName, ddoc, index string, opts SearchOptions) *BookmarkIterator {
+	return c.SearchIteratorContext(context.Background(), dbName, ddoc, index, opts)
+}
+
+// SearchIteratorContext is the same as SearchIterator, but the requests are bound to the given context.
+// The iteration stops as soon as the context is cancelled
+func (c *Client) SearchIteratorContext(ctx context.Context, dbName, ddoc, index string, opts SearchOptions) *BookmarkIterator {
+	if opts.Limit <= 0 {
+		opts.Limit = defaultBookmarkPageSize
+	}
+	// Cloudant does not support the bookmarks with facets and groups
+	opts.Counts, opts.Ranges, opts.GroupField = nil, nil, ""
+	return newBookmarkIterator(ctx, "SearchIterator", "rows", opts.Bookmark, opts.Limit, func(ctx context.Context, bookmark string) (*http.Response, error) {
+		opts.Bookmark = bookmark
+		return c.sendSearch(ctx, dbName, ddoc, index, opts)
+	})
+}
@@ -0,0 +1,89 @@
+package cloudant
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestSearch(t *testing.T) {
+	srv := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		var opts SearchOptions
+		body, _ := ioutil.ReadAll(r.Body)
+		if r.Method != "POST" || json.Unmarshal(body, &opts) != nil {
+			w.WriteHeader(400)
+			return
+		}
+		switch r.URL.Path {
+		case "/test_db/_design/search/_search/people":
+			if opts.Query != "name:mario" || opts.Counts[0] != "city" || opts.Ranges["age"]["young"] != "[0 TO 30]" || opts.Drilldown[0][1] != "Rome" {
+				w.WriteHeader(400)
+				return
+			}
+			w.Write([]byte(`{"total_rows":1,"bookmark":"b1","rows":[{"id":"1","order":[1.0,0],"fields":{"name":"mario"},
+				"doc":{"_id":"1","name":"mario"},"highlights":{"name":["<b>mario</b>"]}}],
+				"counts":{"city":{"Rome":1,"Milan":0}},"ranges":{"age":{"young":1}}}`))
+		case "/test_db/_partition/p1/_design/search/_search/people":
+			w.Write([]byte(`{"total_rows":2,"groups":[{"by":"Rome","total_rows":2,"rows":[{"id":"p1:1","order":[1.0,0],"fields":{}}]}]}`))
+		default:
+			w.WriteHeader(404)
+		}
+	})
+	defer srv.Close()
+	c := newTestClient(srv)
+	result, err := c.Search("test_db", "search", "people", SearchOptions{
+		Query:           "name:mario",
+		IncludeDocs:     true,
+		Counts:          []string{"city"},
+		Ranges:          map[string]map[string]string{"age": {"young": "[0 TO 30]"}},
+		Drilldown:       [][]string{{"city", "Rome"}},
+		HighlightFields: []string{"name"},
+		HighlightPreTag: "<b>",
+	})
+	if err != nil || result.TotalRows != 1 || result.Bookmark != "b1" || result.Counts["city"]["Rome"] != 1 || result.Ranges["age"]["young"] != 1 {
+		t.Fatal("Unexpected result ", result, err)
+	}
+	var person testPerson
+	if row := result.Rows[0]; row.Highlights["name"][0] != "<b>mario</b>" || row.Fields["name"] != "mario" || row.DecodeDoc(&person) != nil || person.Name != "mario" {
+		t.Error("Unexpected row ", row)
+	}
+	grouped, err := c.Search("test_db", "_design/search", "people", SearchOptions{Query: "*:*", GroupField: "city", Partition: "p1"})
+	if err != nil || len(grouped.Groups) != 1 || grouped.Groups[0].Rows[0].ID != "p1:1" {
+		t.Error("Unexpected groups ", grouped, err)
+	}
+	if _, err = c.Search("test_db", "search", "people", SearchOptions{}); err == nil {
+		t.Error("Expected error for missing query")
+	}
+}
+
+func TestSearchIterator(t *testing.T) {
+	srv := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		var opts SearchOptions
+		body, _ := ioutil.ReadAll(r.Body)
+		json.Unmarshal(body, &opts)
+		start, _ := strconv.Atoi(opts.Bookmark)
+		var rows []string
+		for i := start; i < 7 && len(rows) < opts.Limit; i++ {
+			rows = append(rows, fmt.Sprintf(`{"id":"%d","order":[%d],"fields":{}}`, i, i))
+		}
+		fmt.Fprintf(w, `{"total_rows":7,"bookmark":"%d","rows":[%s]}`, start+len(rows), strings.Join(rows, ","))
+	})
+	defer srv.Close()
+	it := newTestClient(srv).SearchIterator("test_db", "search", "people", SearchOptions{Query: "*:*", Limit: 3})
+	defer it.Close()
+	var ids []string
+	for it.Next() {
+		var row SearchRow
+		if err := it.Decode(&row); err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, row.ID)
+	}
+	if it.Err() != nil || strings.Join(ids, "") != "0123456" || it.Bookmark() != "7" {
+		t.Error("Unexpected results ", ids, it.Err())
+	}
+}
@@ -0,0 +1,153 @@
+package cloudant
+
+// Selector is delegated to save the condition used by Cloudant Query for select the documents.
+// It can be composed with Field and the logical operators instead of writing the JSON:
+//
+//	selector := cloudant.Field("age").Gte(18).And(cloudant.Or(
+//		cloudant.Field("city").Eq("Rome"),
+//		cloudant.Field("tags").ElemMatch(cloudant.Field("").Eq("vip")),
+//	))
+//
+// https://cloud.ibm.com/docs/services/Cloudant?topic=cloudant-query#selector-syntax
+type Selector map[string]interface{}
+
+// FieldCondition is delegated to build the conditions related to a field of the document.
+// Nested fields are separated by dots (ex: address.city)
+type FieldCondition struct {
+	name string
+}
+
+// Field is delegated to start a condition on the given field.
+// An empty name is used for the elements of an array, inside ElemMatch and AllMatch
+func Field(name string) FieldCondition {
+	return FieldCondition{name: name}
+}
+
+// operator is delegated to build the selector {"field": {"operator": value}}.
+// The field is omitted when the name is empty
+func (f FieldCondition) operator(op string, value interface{}) Selector {
+	if f.name == "" {
+		return Selector{op: value}
+	}
+	return Selector{f.name: Selector{op: value}}
+}
+
+// Eq match the documents where the field is equal to the given value
+func (f FieldCondition) Eq(value interface{}) Selector {
+	return f.operator("$eq", value)
+}
+
+// Ne match the documents where the field is not equal to the given value
+func (f FieldCondition) Ne(value interface{}) Selector {
+	return f.operator("$ne", value)
+}
+
+// Gt match the documents where the field is greater than the given value
+func (f FieldCondition) Gt(value interface{}) Selector {
+	return f.operator("$gt", value)
+}
+
+// Gte match the documents where the field is greater or equal than the given value
+func (f FieldCondition) Gte(value interface{}) Selector {
+	return f.operator("$gte", value)
+}
+
+// Lt match the documents where the field is lower than the given value
+func (f FieldCondition) Lt(value interface{}) Selector {
+	return f.operator("$lt", value)
+}
+
+// Lte match the documents where the field is lower or equal than the given value
+func (f FieldCondition) Lte(value interface{}) Selector {
+	return f.operator("$lte", value)
+}
+
+// In match the documents where the field is equal to one of the given values
+func (f FieldCondition) In(values ...interface{}) Selector {
+	return f.operator("$in", values)
+}
+
+// Nin match the documents where the field is not equal to any of the given values
+func (f FieldCondition) Nin(values ...interface{}) Selector {
+	return f.operator("$nin", values)
+}
+
+// Exists match the documents where the field exists (or not)
+func (f FieldCondition) Exists(exists bool) Selector {
+	return f.operator("$exists", exists)
+}
+
+// Type match the documents where the field has the given type (null, boolean, number, string, array, object)
+func (f FieldCondition) Type(t string) Selector {
+	return f.operator("$type", t)
+}
+
+// Regex match the documents where the field is a string that match the given regular expression
+func (f FieldCondition) Regex(pattern string) Selector {
+	return f.operator("$regex", pattern)
+}
+
+// Size match the documents where the field is an array with the given length
+func (f FieldCondition) Size(size int) Selector {
+	return f.operator("$size", size)
+}
+
+// Mod match the documents where the field is an integer and field % divisor == remainder
+func (f FieldCondition) Mod(divisor, remainder int) Selector {
+	return f.operator("$mod", []int{divisor, remainder})
+}
+
+// All match the documents where the field is an array that contains all the given values
+func (f FieldCondition) All(values ...interface{}) Selector {
+	return f.operator("$all", values)
+}
+
+// ElemMatch match the documents where the field is an array with at least one element that match the given selector
+func (f FieldCondition) ElemMatch(selector Selector) Selector {
+	return f.operator("$elemMatch", selector)
+}
+
+// AllMatch match the documents where the field is an array with all the elements that match the given selector
+func (f FieldCondition) AllMatch(selector Selector) Selector {
+	return f.operator("$allMatch", selector)
+}
+
+// KeyMapMatch match the documents where the field is an object with at least one key that match the given selector
+func (f FieldCondition) KeyMapMatch(selector Selector) Selector {
+	return f.operator("$keyMapMatch", selector)
+}
+
+// Not match the documents where the field does not match the given selector
+func (f FieldCondition) Not(selector Selector) Selector {
+	return f.operator("$not", selector)
+}
+
+// And match the documents that match all the given selectors
+func And(selectors ...Selector) Selector {
+	return Selector{"$and": selectors}
+}
+
+// Or match the documents that match at least one of the given selectors
+func Or(selectors ...Selector) Selector {
+	return Selector{"$or": selectors}
+}
+
+// Nor match the documents that do not match any of the given selectors
+func Nor(selectors ...Selector) Selector {
+	return Selector{"$nor": selectors}
+}
+
+// Not match the documents that do not match the given selector
+func Not(selector Selector) Selector {
+	return Selector{"$not": selector}
+}
+
+// And is delegated to combine the selector with the given ones, all of them have to match
+func (s Selector) And(selectors ...Selector) Selector {
+	return And(append([]Selector{s}, selectors...)...)
+}
+
+// Or is delegated to combine the selector with the given ones, at least one of them have to match
+func (s Selector) Or(selectors ...Selector) Selector {
+	return Or(append([]Selector{s}, selectors...)...)
+}
@@ -0,0 +1,118 @@
+package cloudant
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// sessionCookieName is the name of the cookie used by Cloudant for authenticate the session
+const sessionCookieName = "AuthSession"
+
+// requestSessionCookie is delegated to inititialize a new session cookie based
+// https://cloud.ibm.com/docs/services/Cloudant?topic=cloudant-authentication#cookie-authentication
+// The method use the username and password for initialize a new Cloudant session and return the `AuthSession` cookie
+func requestSessionCookie(ctx context.Context, httpClient *http.Client, sessionURL, username, password string) (*http.Cookie, error) {
+	if strings.TrimSpace(username) == "" || strings.TrimSpace(password) == "" {
+		zap.S().Error("requestSessionCookie | Empty user or pass")
+		return nil, fmt.Errorf("%w: username or password not provided", ErrInvalidArgument)
+	}
+	headers := newHeader(`Accept`, `application/json`, `Content-Type`, `application/x-www-form-urlencoded`)
+	encoded := url.Values{}
+	encoded.Set("name", username)
+	encoded.Set("password", password)
+
+	zap.S().Debug("requestSessionCookie | Sending request to URL: [", sessionURL, "]")
+	resp, err := sendRequest(ctx, httpClient, nil, `POST`, sessionURL, headers, []byte(encoded.Encode()))
+	zap.S().Debug("requestSessionCookie | HTTP Code: ", resp.StatusCode, " | Body: ", string(resp.Body))
+	if err != nil {
+		zap.S().Error("requestSessionCookie | ERROR! Something went wrong ... | Err: ", err)
+		return nil, err
+	}
+	// Filter only the "AuthSession" cookie from the "Set-Cookie" headers
+	for _, cookie := range (&http.Response{Header: resp.Header}).Cookies() {
+		if cookie.Name == sessionCookieName {
+			zap.S().Debug("requestSessionCookie | Auth cookie found!")
+			return cookie, nil
+		}
+	}
+	zap.S().Error("requestSessionCookie | Unable to retrieve cookie")
+	return nil, fmt.Errorf("cloudant: %s cookie not found in %s response", sessionCookieName, sessionURL)
+}
+
+// CookieSession is delegated to authenticate the requests using the Cloudant session cookie.
+// The lifetime of the cookie is tracked using the `Max-Age`/`Expires` attribute, and a new session is
+// initialized before the expiration. It is safe for concurrent use
+type CookieSession struct {
+	username   string
+	password   string
+	sessionURL string
+	httpClient *http.Client
+	cache      *cachedCredential
+}
+
+// NewCookieSession is delegated to initialize a new cookie based session for the given Cloudant instance.
+// The session is initialized lazily, during the first call to Cookie
+// dbURL: URL related to the Cloudant instance
+func NewCookieSession(dbURL, username, password string, httpClient *http.Client) *CookieSession {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	s := &CookieSession{username: username, password: password, sessionURL: dbURL + `/_session`, httpClient: httpClient}
+	s.cache = newCachedCredential(s.fetch)
+	return s
+}
+
+// Cookie is delegated to return a valid session cookie, in the `AuthSession=value` format used by the 'Cookie' header
+func (s *CookieSession) Cookie(ctx context.Context) (string, error) {
+	return s.cache.get(ctx)
+}
+
+// Invalidate is delegated to discard the cached cookie, a new session will be initialized on the next call to Cookie.
+// It is used when Cloudant reject the cookie (401) before its expiration
+func (s *CookieSession) Invalidate() {
+	s.cache.invalidate()
+}
+
+// Expiration is delegated to return the expiration of the cached cookie.
+// A zero time is returned if the server does not provide the lifetime of the cookie
+func (s *CookieSession) Expiration() time.Time {
+	return s.cache.expires()
+}
+
+// Logout is delegated to close the current session
+// https://cloud.ibm.com/docs/services/Cloudant?topic=cloudant-authentication#deleting-a-session
+func (s *CookieSession) Logout(ctx context.Context) error {
+	s.cache.mu.Lock()
+	cookie := s.cache.value
+	s.cache.mu.Unlock()
+	if cookie == "" {
+		zap.S().Debug("Logout | Session not initialized, nothing to do")
+		return nil
+	}
+	s.Invalidate()
+	headers := newHeader(`Accept`, `application/json`, `Cookie`, cookie)
+	resp, err := sendRequest(ctx, s.httpClient, nil, `DELETE`, s.sessionURL, headers, nil)
+	zap.S().Debug("Logout | HTTP Code: ", resp.StatusCode, " | Body: ", string(resp.Body))
+	return err
+}
+
+// fetch is delegated to initialize a new session, computing the related expiration from the cookie attributes
+func (s *CookieSession) fetch(ctx context.Context) (string, time.Time, error) {
+	cookie, err := requestSessionCookie(ctx, s.httpClient, s.sessionURL, s.username, s.password)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	var expiration time.Time
+	if cookie.MaxAge > 0 {
+		expiration = s.cache.now().Add(time.Duration(cookie.MaxAge) * time.Second)
+	} else if !cookie.Expires.IsZero() {
+		expiration = cookie.Expires
+	}
+	return sessionCookieName + `=` + cookie.Value, expiration, nil
+}
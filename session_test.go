@@ -0,0 +1,108 @@
+package cloudant
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTestSessionServer is delegated to initialize a fake Cloudant instance that track the sessions.
+// Every cookie returned is named `session-N`, where N is the number of the login; only the last one is accepted
+func newTestSessionServer(logins, logouts *int32) *httptest.Server {
+	return httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current := "session-" + strconv.Itoa(int(atomic.LoadInt32(logins)))
+		if r.URL.Path == "/_session" {
+			switch r.Method {
+			case "POST":
+				n := atomic.AddInt32(logins, 1)
+				http.SetCookie(w, &http.Cookie{Name: "AuthSession", Value: "session-" + strconv.Itoa(int(n)), MaxAge: 600})
+				w.Write([]byte(`{"ok":true,"name":"user","roles":[]}`))
+			case "DELETE":
+				if cookie, err := r.Cookie("AuthSession"); err == nil && cookie.Value == current {
+					atomic.AddInt32(logouts, 1)
+				}
+				w.Write([]byte(`{"ok":true}`))
+			}
+			return
+		}
+		if cookie, err := r.Cookie("AuthSession"); err != nil || cookie.Value != current {
+			w.WriteHeader(401)
+			w.Write([]byte(`{"error":"unauthorized","reason":"Session expired"}`))
+			return
+		}
+		w.Write([]byte(`{"couchdb":"Welcome"}`))
+	}))
+}
+
+func TestCookieSession(t *testing.T) {
+	var logins, logouts int32
+	srv := newTestSessionServer(&logins, &logouts)
+	defer srv.Close()
+	s := NewCookieSession(srv.URL, "user", "pass", srv.Client())
+	now := time.Now()
+	s.cache.now = func() time.Time { return now }
+
+	cookie, err := s.Cookie(context.Background())
+	if err != nil || cookie != "AuthSession=session-1" {
+		t.Error("Unexpected cookie ", cookie, err)
+	}
+	if !s.Expiration().Equal(now.Add(10 * time.Minute)) {
+		t.Error("Unexpected expiration ", s.Expiration())
+	}
+	if cookie, _ = s.Cookie(context.Background()); cookie != "AuthSession=session-1" || atomic.LoadInt32(&logins) != 1 {
+		t.Error("Expected cached cookie, got ", cookie)
+	}
+	// Session expired, a new one is initialized before returning
+	now = now.Add(time.Hour)
+	if cookie, _ = s.Cookie(context.Background()); cookie != "AuthSession=session-2" {
+		t.Error("Expected renewed cookie, got ", cookie)
+	}
+}
+
+func TestCookieSessionUnauthorizedRetry(t *testing.T) {
+	var logins, logouts int32
+	srv := newTestSessionServer(&logins, &logouts)
+	defer srv.Close()
+	c, err := NewClient(Conf{Host: srv.URL[len("https://"):], Username: "user", Password: "pass"}, WithHTTPClient(srv.Client()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.PingCloudant(); err != nil {
+		t.Error(err)
+	}
+	// The session is closed server side by someone else
+	atomic.AddInt32(&logins, 1)
+	if err := c.PingCloudant(); err != nil {
+		t.Error(err)
+	}
+	if atomic.LoadInt32(&logins) != 3 {
+		t.Error("Expected a new session after 401, got ", logins)
+	}
+}
+
+func TestLogout(t *testing.T) {
+	var logins, logouts int32
+	srv := newTestSessionServer(&logins, &logouts)
+	defer srv.Close()
+	c, err := NewClient(Conf{Host: srv.URL[len("https://"):], Username: "user", Password: "pass"}, WithHTTPClient(srv.Client()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.PingCloudant(); err != nil {
+		t.Error(err)
+	}
+	if err := c.Logout(); err != nil {
+		t.Error(err)
+	}
+	if atomic.LoadInt32(&logouts) != 1 {
+		t.Error("Expected DELETE /_session with the current cookie")
+	}
+	// Nothing to close if the session is not initialized
+	if err := c.Logout(); err != nil || atomic.LoadInt32(&logouts) != 1 {
+		t.Error("Unexpected logout ", err)
+	}
+}
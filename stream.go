@@ -0,0 +1,110 @@
+package cloudant
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// rowDecoder is delegated to decode incrementally the elements of an array contained in a JSON object, like the
+// `rows` of `_all_docs` and views or the `docs` of `_find`, without load the whole response in memory.
+// The other fields of the object are saved in meta, the ones after the array are available only when the array is consumed
+type rowDecoder struct {
+	body  io.ReadCloser
+	dec   *json.Decoder
+	field string
+	meta  map[string]json.RawMessage
+	done  bool
+}
+
+// newRowDecoder is delegated to read the given body until the beginning of the array contained in field
+func newRowDecoder(body io.ReadCloser, field string) (*rowDecoder, error) {
+	d := &rowDecoder{body: body, dec: json.NewDecoder(body), field: field, meta: make(map[string]json.RawMessage)}
+	if err := d.expect(json.Delim('{')); err != nil {
+		body.Close()
+		return nil, err
+	}
+	found, err := d.readFields()
+	if err != nil {
+		body.Close()
+		return nil, err
+	}
+	if !found {
+		d.done = true
+		body.Close()
+	}
+	return d, nil
+}
+
+// expect is delegated to read the next token, returning an error if it is not the given delimiter
+func (d *rowDecoder) expect(delim json.Delim) error {
+	token, err := d.dec.Token()
+	if err != nil {
+		return fmt.Errorf("cloudant: unable to decode response: %w", err)
+	}
+	if token != delim {
+		return fmt.Errorf("cloudant: unable to decode response: expected %s, found %v", delim, token)
+	}
+	return nil
+}
+
+// readFields is delegated to read the fields of the object saving them in meta, until the array or the end of the object.
+// Return true if the array is found
+func (d *rowDecoder) readFields() (bool, error) {
+	for d.dec.More() {
+		token, err := d.dec.Token()
+		if err != nil {
+			return false, fmt.Errorf("cloudant: unable to decode response: %w", err)
+		}
+		key, _ := token.(string)
+		if key == d.field {
+			if err = d.expect(json.Delim('[')); err != nil {
+				return false, err
+			}
+			return true, nil
+		}
+		var value json.RawMessage
+		if err = d.dec.Decode(&value); err != nil {
+			return false, fmt.Errorf("cloudant: unable to decode field %s: %w", key, err)
+		}
+		d.meta[key] = value
+	}
+	return false, nil
+}
+
+// next is delegated to decode the next element of the array into v.
+// Return false when the array is consumed, after reading the remaining fields of the object
+func (d *rowDecoder) next(v interface{}) (bool, error) {
+	if d.done {
+		return false, nil
+	}
+	if d.dec.More() {
+		if err := d.dec.Decode(v); err != nil {
+			return false, fmt.Errorf("cloudant: unable to decode %s: %w", d.field, err)
+		}
+		return true, nil
+	}
+	d.done = true
+	defer d.body.Close()
+	if err := d.expect(json.Delim(']')); err != nil {
+		return false, err
+	}
+	if _, err := d.readFields(); err != nil {
+		return false, err
+	}
+	return false, nil
+}
+
+// decodeMeta is delegated to decode the given field of the object into v, if present
+func (d *rowDecoder) decodeMeta(field string, v interface{}) error {
+	if value, ok := d.meta[field]; ok {
+		return json.Unmarshal(value, v)
+	}
+	return nil
+}
+
+// close is delegated to release the connection, discarding the elements not yet read
+func (d *rowDecoder) close() error {
+	d.done = true
+	return d.body.Close()
+}
@@ -0,0 +1,120 @@
+package cloudant
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// DefaultIAMURL is the endpoint used for exchange the apikey with a bearer token
+const DefaultIAMURL = "https://iam.cloud.ibm.com/identity/token"
+
+// iamTokenLifetime is the default lifetime of an IAM token
+const iamTokenLifetime = time.Hour
+
+// IAMToken is delegated to save the information returned by IAM after the exchange of the apikey
+// https://cloud.ibm.com/docs/iam?topic=iam-iamtoken_from_apikey#iamtoken_from_apikey
+type IAMToken struct {
+	// Token that have to be used as 'Bearer Authorization'
+	AccessToken string `json:"access_token"`
+	// Type of the token, always 'Bearer'
+	TokenType string `json:"token_type"`
+	// Number of seconds until the expiration of the token
+	ExpiresIn int64 `json:"expires_in"`
+	// Expiration of the token, in seconds since epoch
+	Expiration int64 `json:"expiration"`
+}
+
+// requestIAMToken is delegated to exchange the given apikey with a new IAM token
+func requestIAMToken(ctx context.Context, httpClient *http.Client, tokenURL, apikey string) (IAMToken, error) {
+	var token IAMToken
+	if strings.TrimSpace(apikey) == "" {
+		zap.S().Error("requestIAMToken | Empty apikey")
+		return token, fmt.Errorf("%w: apikey not provided", ErrInvalidArgument)
+	}
+	headers := newHeader(`Accept`, `application/json`, `Content-Type`, `application/x-www-form-urlencoded`)
+	encoded := url.Values{}
+	encoded.Set("grant_type", "urn:ibm:params:oauth:grant-type:apikey")
+	encoded.Set("apikey", apikey)
+	zap.S().Debug("requestIAMToken | Sending request to URL: [", tokenURL, "]")
+	resp, err := sendRequest(ctx, httpClient, nil, `POST`, tokenURL, headers, []byte(encoded.Encode()))
+	zap.S().Debug("requestIAMToken | HTTP Code: ", resp.StatusCode)
+	if err != nil {
+		zap.S().Error("requestIAMToken | ERROR! Something went wrong ... | Err: ", err)
+		return token, err
+	}
+	if err = json.Unmarshal(resp.Body, &token); err != nil {
+		return token, fmt.Errorf("cloudant: unable to decode IAM response: %w", err)
+	}
+	if token.AccessToken = strings.TrimSpace(token.AccessToken); token.AccessToken == "" {
+		return token, fmt.Errorf("cloudant: access_token not found in IAM response")
+	}
+	return token, nil
+}
+
+// IAMTokenSource is delegated to retrieve the IAM token and to keep it valid for the whole lifetime of the client.
+// The token is cached until the 80% of its lifetime is elapsed, then a new one is requested in background while
+// the current one is still used. A token that is alredy expired is refreshed before returning.
+// It is safe for concurrent use
+type IAMTokenSource struct {
+	apikey     string
+	tokenURL   string
+	httpClient *http.Client
+	cache      *cachedCredential
+}
+
+// NewIAMTokenSource is delegated to initialize a new token source for the given apikey.
+// The token is requested lazily, during the first call to Token
+// tokenURL: IAM endpoint used for retrieve the token, DefaultIAMURL if empty
+// httpClient: client used for request the token, http.DefaultClient if nil
+func NewIAMTokenSource(apikey, tokenURL string, httpClient *http.Client) *IAMTokenSource {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	if tokenURL == "" {
+		tokenURL = DefaultIAMURL
+	}
+	ts := &IAMTokenSource{apikey: apikey, tokenURL: tokenURL, httpClient: httpClient}
+	ts.cache = newCachedCredential(ts.fetch)
+	return ts
+}
+
+// Token is delegated to return a valid IAM token.
+// The cached token is returned when it is still valid, scheduling a refresh in background if it is going to expire
+func (ts *IAMTokenSource) Token(ctx context.Context) (string, error) {
+	return ts.cache.get(ctx)
+}
+
+// Invalidate is delegated to discard the cached token, a new one will be requested on the next call to Token.
+// It is used when Cloudant reject a token (401) before its expiration
+func (ts *IAMTokenSource) Invalidate() {
+	ts.cache.invalidate()
+}
+
+// Expiration is delegated to return the expiration of the cached token
+func (ts *IAMTokenSource) Expiration() time.Time {
+	return ts.cache.expires()
+}
+
+// fetch is delegated to request a new token, computing the related expiration from `expiration` or `expires_in`
+func (ts *IAMTokenSource) fetch(ctx context.Context) (string, time.Time, error) {
+	token, err := requestIAMToken(ctx, ts.httpClient, ts.tokenURL, ts.apikey)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	if token.Expiration > 0 {
+		return token.AccessToken, time.Unix(token.Expiration, 0), nil
+	}
+	lifetime := time.Duration(token.ExpiresIn) * time.Second
+	if lifetime <= 0 {
+		// No timing provided, fallback to the default lifetime of an IAM token
+		lifetime = iamTokenLifetime
+	}
+	return token.AccessToken, ts.cache.now().Add(lifetime), nil
+}
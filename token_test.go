@@ -0,0 +1,147 @@
+package cloudant
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTestIAMServer is delegated to initialize a fake IAM endpoint.
+// Every token returned is named `token-N`, where N is the number of the request
+func newTestIAMServer(counter *int32, expiresIn int) *httptest.Server {
+	return newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/identity/token" {
+			r.ParseForm()
+			if r.Form.Get("apikey") != "apikey" {
+				w.WriteHeader(400)
+				w.Write([]byte(`{"errorCode":"BXNIM0415E","errorMessage":"Provided API key could not be found"}`))
+				return
+			}
+			n := atomic.AddInt32(counter, 1)
+			w.Write([]byte(`{"access_token":"token-` + strconv.Itoa(int(n)) + `","token_type":"Bearer","expires_in":` + strconv.Itoa(expiresIn) + `}`))
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer token-2" {
+			w.WriteHeader(401)
+			return
+		}
+		w.Write([]byte(`{"couchdb":"Welcome"}`))
+	})
+}
+
+func newTestTokenSource(srv *httptest.Server, apikey string) *IAMTokenSource {
+	return NewIAMTokenSource(apikey, srv.URL+"/identity/token", srv.Client())
+}
+
+func TestIAMTokenSource(t *testing.T) {
+	var counter int32
+	srv := newTestIAMServer(&counter, 3600)
+	defer srv.Close()
+	ts := newTestTokenSource(srv, "apikey")
+	now := time.Now()
+	ts.cache.now = func() time.Time { return now }
+
+	token, err := ts.Token(context.Background())
+	if err != nil || token != "token-1" {
+		t.Error("Unexpected token ", token, err)
+	}
+	if !ts.Expiration().Equal(now.Add(time.Hour)) {
+		t.Error("Unexpected expiration ", ts.Expiration())
+	}
+	// Cached token
+	if token, _ = ts.Token(context.Background()); token != "token-1" || atomic.LoadInt32(&counter) != 1 {
+		t.Error("Expected cached token, got ", token)
+	}
+	// Token expired, a new one is requested before returning
+	now = now.Add(2 * time.Hour)
+	if token, _ = ts.Token(context.Background()); token != "token-2" {
+		t.Error("Expected refreshed token, got ", token)
+	}
+	ts.Invalidate()
+	if token, _ = ts.Token(context.Background()); token != "token-3" {
+		t.Error("Expected new token after invalidate, got ", token)
+	}
+}
+
+func TestIAMTokenSourceBackgroundRefresh(t *testing.T) {
+	var counter int32
+	srv := newTestIAMServer(&counter, 3600)
+	defer srv.Close()
+	ts := newTestTokenSource(srv, "apikey")
+	now := time.Now()
+	var mu sync.Mutex
+	ts.cache.now = func() time.Time { mu.Lock(); defer mu.Unlock(); return now }
+	ts.Token(context.Background())
+
+	// 50 minutes later the token is still valid, but it have to be refreshed
+	mu.Lock()
+	now = now.Add(50 * time.Minute)
+	mu.Unlock()
+	if token, _ := ts.Token(context.Background()); token != "token-1" {
+		t.Error("Expected current token while refreshing, got ", token)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for atomic.LoadInt32(&counter) != 2 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	for time.Now().Before(deadline) {
+		if token, _ := ts.Token(context.Background()); token == "token-2" {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Error("Token not refreshed in background")
+}
+
+func TestIAMTokenSourceConcurrent(t *testing.T) {
+	var counter int32
+	srv := newTestIAMServer(&counter, 3600)
+	defer srv.Close()
+	ts := newTestTokenSource(srv, "apikey")
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := ts.Token(context.Background()); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	if atomic.LoadInt32(&counter) != 1 {
+		t.Error("Expected a single request to IAM, got ", counter)
+	}
+}
+
+func TestIAMTokenSourceError(t *testing.T) {
+	var counter int32
+	srv := newTestIAMServer(&counter, 3600)
+	defer srv.Close()
+	if _, err := newTestTokenSource(srv, "wrong").Token(context.Background()); !IsBadRequest(err) {
+		t.Error("Expected bad request, got ", err)
+	}
+}
+
+func TestIAMUnauthorizedRetry(t *testing.T) {
+	var counter int32
+	srv := newTestIAMServer(&counter, 3600)
+	defer srv.Close()
+	conf := Conf{URL: srv.URL, Apikey: "apikey", IAMURL: srv.URL + "/identity/token"}
+	c, err := NewClient(conf, WithHTTPClient(srv.Client()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The first token is rejected by the server, the second one is accepted
+	if err := c.PingCloudant(); err != nil {
+		t.Error(err)
+	}
+	if atomic.LoadInt32(&counter) != 2 {
+		t.Error("Expected a token refresh after 401, got ", counter)
+	}
+}
@@ -0,0 +1,158 @@
+package cloudant
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"go.uber.org/zap"
+)
+
+// Document is delegated to save the metadata managed by Cloudant for every document.
+// It is meant to be embedded in the structs used with Get, Put and Create:
+//
+//	type Person struct {
+//		cloudant.Document
+//		Name string `json:"name"`
+//	}
+type Document struct {
+	// Key of the document
+	ID string `json:"_id,omitempty"`
+	// Revision of the document, updated after every successful write
+	Rev string `json:"_rev,omitempty"`
+	// True if the document is deleted
+	Deleted bool `json:"_deleted,omitempty"`
+	// Attachments of the document, indexed by name
+	Attachments map[string]Attachment `json:"_attachments,omitempty"`
+}
+
+// Attachment is delegated to save the information related to an attachment of a document
+// https://cloud.ibm.com/docs/services/Cloudant?topic=cloudant-attachments
+type Attachment struct {
+	// MIME type of the attachment
+	ContentType string `json:"content_type"`
+	// Content of the attachment, only when it is inlined (base64 encoded in the JSON)
+	Data []byte `json:"data,omitempty"`
+	// Digest of the content
+	Digest string `json:"digest,omitempty"`
+	// Size of the content in bytes
+	Length int64 `json:"length,omitempty"`
+	// Revision of the document when the attachment was added
+	RevPos int `json:"revpos,omitempty"`
+	// True if only the metadata of the attachment are present
+	Stub bool `json:"stub,omitempty"`
+}
+
+// Meta is delegated to return the metadata of the document, it is used for implement Identifiable
+func (d *Document) Meta() *Document {
+	return d
+}
+
+// Identifiable is implemented by every struct that embeds Document.
+// It is used by Put and Create for read the `_id` and write back the new `_rev`
+type Identifiable interface {
+	Meta() *Document
+}
+
+// meta is delegated to return the metadata of the given document, nil if it does not embed Document.
+// A nil pointer has no metadata: the promoted Meta would dereference it
+func meta(doc interface{}) *Document {
+	if v := reflect.ValueOf(doc); v.Kind() == reflect.Ptr && v.IsNil() {
+		return nil
+	}
+	if id, ok := doc.(Identifiable); ok {
+		return id.Meta()
+	}
+	return nil
+}
+
+// Get is delegated to retrieve the document with the given '_id' and decode it into a new T
+// databaseName: DB that contains the document
+// _id: Key of the document
+func Get[T any](c *Client, databaseName, _id string) (T, error) {
+	return GetContext[T](context.Background(), c, databaseName, _id)
+}
+
+// GetContext is the same as Get, but the request is bound to the given context
+func GetContext[T any](ctx context.Context, c *Client, databaseName, _id string) (T, error) {
+	var doc T
+	zap.S().Debug("Get | Retrieving document from DB [", databaseName, "] with '_id': [", _id, "]")
+	if databaseName == "" || _id == "" {
+		return doc, fmt.Errorf("%w: DB name and '_id' are mandatory", ErrInvalidArgument)
+	}
+	headers := newHeader(`Accept`, `application/json`)
+	response, err := c.send(ctx, `GET`, c.docURL(databaseName, _id), headers, nil)
+	if err != nil {
+		zap.S().Debug("Get | ERROR! Unable to retrieve the document | Err: ", err)
+		return doc, err
+	}
+	if err = json.Unmarshal(response.Body, &doc); err != nil {
+		return doc, fmt.Errorf("cloudant: unable to decode document %s: %w", _id, err)
+	}
+	return doc, nil
+}
+
+// Put is delegated to create or update the given document, using the `_id` and the `_rev` stored in the embedded Document.
+// The new revision is written back into the document and returned
+// databaseName: DB that contains the document
+// doc: Document to save, T have to embed Document (or implement Identifiable)
+// NOTE: If the '_rev' is not the most recent one, IsConflict will return true for the returned error
+func Put[T any](c *Client, databaseName string, doc *T) (string, error) {
+	return PutContext(context.Background(), c, databaseName, doc)
+}
+
+// PutContext is the same as Put, but the request is bound to the given context
+func PutContext[T any](ctx context.Context, c *Client, databaseName string, doc *T) (string, error) {
+	if doc == nil {
+		return "", fmt.Errorf("%w: document not provided", ErrInvalidArgument)
+	}
+	m := meta(doc)
+	if m == nil {
+		return "", fmt.Errorf("%w: %T does not embed cloudant.Document", ErrInvalidArgument, doc)
+	}
+	// The `_rev` is alredy in the body, no need to send it as parameter
+	rev, err := c.UpdateDocumentContext(ctx, databaseName, m.ID, "", doc)
+	if err != nil {
+		return "", err
+	}
+	m.Rev = rev
+	return rev, nil
+}
+
+// Create is delegated to insert the given document as a new document.
+// When the `_id` is empty, it is generated by Cloudant. If T embeds Document, the `_id` and the `_rev` assigned
+// by Cloudant are written back into the document
+// databaseName: DB that we want to use for store the document
+// doc: Document to insert
+// Return the `_id` and the `_rev` of the new document
+func Create[T any](c *Client, databaseName string, doc *T) (string, string, error) {
+	return CreateContext(context.Background(), c, databaseName, doc)
+}
+
+// CreateContext is the same as Create, but the request is bound to the given context
+func CreateContext[T any](ctx context.Context, c *Client, databaseName string, doc *T) (string, string, error) {
+	zap.S().Debug("Create | Inserting new document into DB [", databaseName, "]")
+	if databaseName == "" || doc == nil {
+		return "", "", fmt.Errorf("%w: DB name and document are mandatory", ErrInvalidArgument)
+	}
+	body, err := marshalDocument(doc)
+	if err != nil {
+		zap.S().Error("Create | Unable to encode document | Err: ", err)
+		return "", "", err
+	}
+	headers := newHeader(`Accept`, `application/json`, `Content-Type`, `application/json`)
+	response, err := c.send(ctx, `POST`, c.databaseURL(databaseName), headers, body)
+	if err != nil {
+		zap.S().Debug("Create | ERROR! Unable to create the document | Err: ", err)
+		return "", "", err
+	}
+	result, err := decodeDocumentResponse(response)
+	if err != nil {
+		return "", "", err
+	}
+	if m := meta(doc); m != nil {
+		m.ID, m.Rev = result.ID, result.Rev
+	}
+	return result.ID, result.Rev, nil
+}
@@ -0,0 +1,88 @@
+package cloudant
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"testing"
+)
+
+type testPerson struct {
+	Document
+	Name string `json:"name"`
+}
+
+func TestGet(t *testing.T) {
+	srv := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/test_db/1" {
+			w.WriteHeader(404)
+			return
+		}
+		w.Write([]byte(`{"_id":"1","_rev":"1-abc","name":"test","_attachments":{"a.txt":{"content_type":"text/plain","stub":true}}}`))
+	})
+	defer srv.Close()
+	c := newTestClient(srv)
+	p, err := Get[testPerson](c, "test_db", "1")
+	if err != nil || p.ID != "1" || p.Rev != "1-abc" || p.Name != "test" || !p.Attachments["a.txt"].Stub {
+		t.Error("Unexpected document ", p, err)
+	}
+	if _, err = Get[testPerson](c, "test_db", "2"); !IsNotFound(err) {
+		t.Error("Expected not found, got ", err)
+	}
+}
+
+func TestPut(t *testing.T) {
+	srv := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		var p testPerson
+		body, _ := ioutil.ReadAll(r.Body)
+		if r.Method != "PUT" || r.URL.Path != "/test_db/1" || json.Unmarshal(body, &p) != nil || p.Name != "test" {
+			w.WriteHeader(400)
+			return
+		}
+		if p.Rev != "1-abc" {
+			w.WriteHeader(409)
+			return
+		}
+		w.WriteHeader(201)
+		w.Write([]byte(`{"ok":true,"id":"1","rev":"2-def"}`))
+	})
+	defer srv.Close()
+	c := newTestClient(srv)
+	p := testPerson{Document: Document{ID: "1", Rev: "1-abc"}, Name: "test"}
+	if rev, err := Put(c, "test_db", &p); err != nil || rev != "2-def" || p.Rev != "2-def" {
+		t.Error("Expected revision written back, got ", p.Rev, err)
+	}
+	// The revision is now outdated for the fake server
+	if _, err := Put(c, "test_db", &p); !IsConflict(err) {
+		t.Error("Expected conflict, got ", err)
+	}
+	if _, err := Put(c, "test_db", &struct{ Name string }{"test"}); !errors.Is(err, ErrInvalidArgument) {
+		t.Error("Expected invalid argument for struct without Document, got ", err)
+	}
+	if _, err := Put[testPerson](c, "test_db", nil); !errors.Is(err, ErrInvalidArgument) {
+		t.Error("Expected invalid argument for nil document, got ", err)
+	}
+}
+
+func TestCreate(t *testing.T) {
+	srv := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		if r.Method != "POST" || r.URL.Path != "/test_db" || string(body) != `{"name":"test"}` {
+			w.WriteHeader(400)
+			return
+		}
+		w.WriteHeader(201)
+		w.Write([]byte(`{"ok":true,"id":"generated","rev":"1-abc"}`))
+	})
+	defer srv.Close()
+	c := newTestClient(srv)
+	p := testPerson{Name: "test"}
+	if id, rev, err := Create(c, "test_db", &p); err != nil || id != "generated" || rev != "1-abc" || p.ID != id || p.Rev != rev {
+		t.Error("Expected id and revision written back, got ", p.Document, err)
+	}
+	m := map[string]string{"name": "test"}
+	if id, _, err := Create(c, "test_db", &m); err != nil || id != "generated" {
+		t.Error("Expected document created from map, got ", id, err)
+	}
+}
@@ -0,0 +1,177 @@
+package cloudant
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"go.uber.org/zap"
+)
+
+// ViewRow is delegated to save a row of a view, with the key of type K and the value of type V.
+// For a reduced view, ID is empty and Key is the group key (null without Group)
+type ViewRow[K, V any] struct {
+	// `_id` of the document that emitted the row
+	ID string `json:"id,omitempty"`
+	// Key emitted by the map function, or the group key
+	Key K `json:"key"`
+	// Value emitted by the map function, or the result of the reduce
+	Value V `json:"value"`
+	// Content of the document, only with IncludeDocs
+	Doc json.RawMessage `json:"doc,omitempty"`
+	// Error related to the key, like `not_found`, only with Keys
+	Error string `json:"error,omitempty"`
+}
+
+// DecodeDoc is delegated to decode the content of the document into v, only with IncludeDocs
+func (r ViewRow[K, V]) DecodeDoc(v interface{}) error {
+	if len(r.Doc) == 0 {
+		return fmt.Errorf("cloudant: document %s not included in the row", r.ID)
+	}
+	return json.Unmarshal(r.Doc, v)
+}
+
+// ViewResult is delegated to save the response of a view
+type ViewResult[K, V any] struct {
+	// Number of rows of the view, not available for a reduced view
+	TotalRows int `json:"total_rows"`
+	// Position of the first row in the view
+	Offset int `json:"offset"`
+	// Rows that match the options
+	Rows []ViewRow[K, V] `json:"rows"`
+}
+
+// QueryView is delegated to query the given view, decoding the keys as K and the values as V.
+// Use json.RawMessage or interface{} for the types that are not known in advance
+// https://cloud.ibm.com/docs/services/Cloudant?topic=cloudant-using-views#using-views
+// dbName: DB that contains the design document
+// ddoc: name of the design document, with or without the `_design/` prefix
+// view: name of the view
+// opts: filters, reduce and partition of the rows
+func QueryView[K, V any](c *Client, dbName, ddoc, view string, opts ViewOptions) (ViewResult[K, V], error) {
+	return QueryViewContext[K, V](context.Background(), c, dbName, ddoc, view, opts)
+}
+
+// QueryViewContext is the same as QueryView, but the request is bound to the given context
+func QueryViewContext[K, V any](ctx context.Context, c *Client, dbName, ddoc, view string, opts ViewOptions) (ViewResult[K, V], error) {
+	var result ViewResult[K, V]
+	rows, err := c.GetViewContext(ctx, dbName, ddoc, view, opts)
+	if err != nil {
+		return result, err
+	}
+	if err = json.Unmarshal([]byte(rows), &result); err != nil {
+		return result, fmt.Errorf("cloudant: unable to decode rows of %s/%s: %w", ddoc, view, err)
+	}
+	return result, nil
+}
+
+// ViewIterator is delegated to iterate over the rows of a view, decoding them while they are received.
+// It is meant for the views with a large number of rows, that are not loaded in memory. It is not safe for concurrent use
+//
+//	it := cloudant.StreamView[string, int](client, "db", "users", "by_name", cloudant.ViewOptions{})
+//	defer it.Close()
+//	for it.Next() {
+//		fmt.Println(it.Row().Key, it.Row().Value)
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type ViewIterator[K, V any] struct {
+	c      *Client
+	ctx    context.Context
+	dbName string
+	ddoc   string
+	view   string
+	opts   ViewOptions
+
+	page      *rowDecoder
+	row       ViewRow[K, V]
+	totalRows int
+	err       error
+	done      bool
+}
+
+// StreamView is delegated to return an iterator over the rows of the given view.
+// The request is sent during the first call to Next
+// dbName: DB that contains the design document
+// ddoc: name of the design document, with or without the `_design/` prefix
+// view: name of the view
+// opts: filters, reduce and partition of the rows
+func StreamView[K, V any](c *Client, dbName, ddoc, view string, opts ViewOptions) *ViewIterator[K, V] {
+	return StreamViewContext[K, V](context.Background(), c, dbName, ddoc, view, opts)
+}
+
+// StreamViewContext is the same as StreamView, but the request is bound to the given context
+func StreamViewContext[K, V any](ctx context.Context, c *Client, dbName, ddoc, view string, opts ViewOptions) *ViewIterator[K, V] {
+	it := &ViewIterator[K, V]{c: c, ctx: ctx, dbName: dbName, ddoc: ddoc, view: view, opts: opts}
+	if dbName == "" || ddoc == "" || view == "" {
+		it.err = fmt.Errorf("%w: DB name, design document and view are mandatory", ErrInvalidArgument)
+	}
+	return it
+}
+
+// Next is delegated to advance to the next row.
+// Return false when there are no more rows or an error occurs (see Err)
+func (it *ViewIterator[K, V]) Next() bool {
+	if it.err != nil || it.done {
+		return false
+	}
+	if it.page == nil {
+		if it.err = it.open(); it.err != nil {
+			return false
+		}
+	}
+	var row ViewRow[K, V]
+	ok, err := it.page.next(&row)
+	if err != nil || !ok {
+		it.err = err
+		it.Close()
+		return false
+	}
+	it.row = row
+	return true
+}
+
+// open is delegated to send the request and to read the response until the rows
+func (it *ViewIterator[K, V]) open() error {
+	method, URL, body, err := queryRequest(it.c.designURL(it.dbName, it.opts.Partition, it.ddoc, `_view`, it.view), it.opts)
+	if err != nil {
+		return err
+	}
+	zap.S().Debug("StreamView | Sending request to URL: [", URL, "]")
+	headers := newHeader(`Accept`, `application/json`, `Content-Type`, `application/json`)
+	res, err := it.c.stream(it.ctx, method, URL, headers, body)
+	if err != nil {
+		return err
+	}
+	if it.page, err = newRowDecoder(res.Body, "rows"); err != nil {
+		return err
+	}
+	return it.page.decodeMeta("total_rows", &it.totalRows)
+}
+
+// Row is delegated to return the current row
+func (it *ViewIterator[K, V]) Row() ViewRow[K, V] {
+	return it.row
+}
+
+// TotalRows is delegated to return the number of rows of the view, available after the first call to Next
+func (it *ViewIterator[K, V]) TotalRows() int {
+	return it.totalRows
+}
+
+// Err is delegated to return the error occurred during the iteration, if any
+func (it *ViewIterator[K, V]) Err() error {
+	return it.err
+}
+
+// Close is delegated to stop the iteration, releasing the connection in use.
+// It is safe to call it multiple times
+func (it *ViewIterator[K, V]) Close() error {
+	it.done = true
+	if it.page != nil {
+		it.page.close()
+		it.page = nil
+	}
+	return nil
+}
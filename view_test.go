@@ -0,0 +1,75 @@
+package cloudant
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// testViewResponses are the responses of the fake view server, indexed by path and query
+var testViewResponses = map[string]string{
+	"/test_db/_design/users/_view/by_city?include_docs=true&reduce=false": `{"total_rows":2,"offset":0,"rows":[
+		{"id":"1","key":["Rome",1],"value":10,"doc":{"_id":"1","name":"mario"}},
+		{"id":"2","key":["Rome",2],"value":20,"doc":{"_id":"2","name":"luigi"}}]}`,
+	"/test_db/_design/users/_view/by_city?group_level=1":                                       `{"rows":[{"key":["Milan"],"value":1},{"key":["Rome"],"value":2}]}`,
+	"/test_db/_partition/p1/_design/users/_view/by_city?key=%5B%22Rome%22%2C1%5D&reduce=false": `{"total_rows":1,"offset":0,"rows":[{"id":"p1:1","key":["Rome",1],"value":10}]}`,
+}
+
+// newTestViewServer is delegated to initialize a fake instance that serve testViewResponses
+func newTestViewServer() *httptest.Server {
+	return newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		response, ok := testViewResponses[r.URL.Path+"?"+r.URL.RawQuery]
+		if !ok {
+			w.WriteHeader(404)
+			return
+		}
+		w.Write([]byte(response))
+	})
+}
+
+func TestQueryView(t *testing.T) {
+	srv := newTestViewServer()
+	defer srv.Close()
+	c := newTestClient(srv)
+
+	rows, err := QueryView[[]interface{}, int](c, "test_db", "users", "by_city", ViewOptions{IncludeDocs: true, Reduce: Bool(false)})
+	if err != nil || rows.TotalRows != 2 || len(rows.Rows) != 2 || rows.Rows[1].Value != 20 || rows.Rows[0].Key[0] != "Rome" {
+		t.Fatal("Unexpected rows ", rows, err)
+	}
+	var person testPerson
+	if err = rows.Rows[1].DecodeDoc(&person); err != nil || person.Name != "luigi" {
+		t.Error("Unexpected document ", person, err)
+	}
+
+	grouped, err := QueryView[[]string, int](c, "test_db", "_design/users", "by_city", ViewOptions{GroupLevel: 1})
+	if err != nil || len(grouped.Rows) != 2 || grouped.Rows[1].Key[0] != "Rome" || grouped.Rows[1].Value != 2 || grouped.Rows[1].ID != "" {
+		t.Error("Unexpected grouped rows ", grouped, err)
+	}
+
+	partitioned, err := QueryView[[]interface{}, int](c, "test_db", "users", "by_city", ViewOptions{Key: []interface{}{"Rome", 1}, Reduce: Bool(false), Partition: "p1"})
+	if err != nil || len(partitioned.Rows) != 1 || partitioned.Rows[0].ID != "p1:1" {
+		t.Error("Unexpected partitioned rows ", partitioned, err)
+	}
+
+	if _, err = QueryView[string, int](c, "test_db", "users", "missing", ViewOptions{}); !IsNotFound(err) {
+		t.Error("Expected not found, got ", err)
+	}
+}
+
+func TestStreamView(t *testing.T) {
+	srv := newTestViewServer()
+	defer srv.Close()
+	c := newTestClient(srv)
+	it := StreamView[[]interface{}, int](c, "test_db", "users", "by_city", ViewOptions{IncludeDocs: true, Reduce: Bool(false)})
+	defer it.Close()
+	sum := 0
+	for it.Next() {
+		sum += it.Row().Value
+	}
+	if it.Err() != nil || sum != 30 || it.TotalRows() != 2 {
+		t.Error("Unexpected rows ", sum, it.Err())
+	}
+	if it = StreamView[[]interface{}, int](c, "test_db", "users", "missing", ViewOptions{}); it.Next() || !IsNotFound(it.Err()) {
+		t.Error("Expected not found, got ", it.Err())
+	}
+}